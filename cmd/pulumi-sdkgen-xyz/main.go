@@ -18,13 +18,17 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"github.com/go-openapi/swag"
 	"github.com/pulumi/pulumi-xyz/pkg/gen"
 	"github.com/pulumi/pulumi-xyz/pkg/provider"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tools"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"os"
 	"path"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	dotnetgen "github.com/pulumi/pulumi/pkg/v3/codegen/dotnet"
@@ -34,36 +38,204 @@ import (
 	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 )
 
-func main() {
-	if len(os.Args) < 3 {
-		fmt.Printf("Usage: pulumi-sdkgen-xyz <target-sdk-folder> <version>\n")
-		return
+// options are the settings of the generator. They are read from an optional JSON or YAML config file, and the
+// command line flags take precedence over it.
+type options struct {
+	// Specs are the paths or URLs of the Open API documents.
+	Specs []string `json:"specs"`
+	// Mapping is the path or URL of a file that declares the operations of resources.
+	Mapping string `json:"mapping"`
+	// Name is the name of the Pulumi package.
+	Name string `json:"name"`
+	// DisplayName is the human-readable name of the API.
+	DisplayName string `json:"displayName"`
+	// ProviderDir is the directory that the schema and the metadata of the provider are written to.
+	ProviderDir string `json:"providerDir"`
+	// SdkDir is the directory that the SDKs are written to, one subdirectory per language.
+	SdkDir string `json:"sdkDir"`
+	// Languages are the languages to generate SDKs for.
+	Languages []string `json:"languages"`
+	// Version is the version of the package.
+	Version string `json:"version"`
+	// Reports are the files to write the coverage report to: JSON for files with a `.json` extension, and text
+	// otherwise.
+	Reports []string `json:"reports"`
+}
+
+// supportedLanguages are the languages that SDKs can be generated for.
+var supportedLanguages = []string{"dotnet", "go", "nodejs", "python"}
+
+// stringsFlag is a flag that accepts a comma-separated list of values and may be repeated.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*f = append(*f, v)
+		}
 	}
+	return nil
+}
 
-	targetSdkFolder := os.Args[1]
-	version := os.Args[2]
+func main() {
+	opts, err := parseOptions(os.Args[1:])
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		os.Exit(2)
+	}
 
-	err := emitPackage(targetSdkFolder, version)
+	err = emitPackage(opts)
 	if err != nil {
-		fmt.Printf("Failed: %s", err.Error())
+		fmt.Fprintf(os.Stderr, "Failed: %s\n", err.Error())
+		os.Exit(1)
 	}
 }
 
+// parseOptions builds the generator settings from the command line. The SDK folder and the version may also be
+// given as positional arguments.
+func parseOptions(args []string) (*options, error) {
+	flags := flag.NewFlagSet("pulumi-sdkgen-xyz", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: pulumi-sdkgen-xyz [flags] [<target-sdk-folder> <version>]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	configFile := flags.String("config", "", "path to a JSON or YAML file with the generator settings")
+	var specs, languages, reports stringsFlag
+	flags.Var(&specs, "spec", "path or URL of an Open API spec in JSON or YAML; may be repeated "+
+		"(default \"open-api-spec/todo-backend.json\")")
+	mapping := flags.String("mapping", "", "path or URL of a JSON or YAML file that maps API operations to resources")
+	name := flags.String("name", "", "name of the Pulumi package (default \"xyz\")")
+	displayName := flags.String("display-name", "", "human-readable name of the API")
+	providerDir := flags.String("provider-dir", "", "directory to write the provider schema and metadata to "+
+		"(default \"cmd/pulumi-resource-<name>\")")
+	sdkDir := flags.String("sdk-dir", "", "directory to write the SDKs to (default \"sdk\")")
+	flags.Var(&languages, "languages", "comma-separated list of languages to generate SDKs for "+
+		"(default \"dotnet,go,nodejs,python\")")
+	version := flags.String("version", "", "version of the package")
+	flags.Var(&reports, "report", "file to write the report of mapped and skipped operations to, in JSON if the "+
+		"file has a .json extension or as text otherwise; may be repeated")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	opts := options{}
+	if *configFile != "" {
+		data, err := os.ReadFile(*configFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading config file")
+		}
+		if swag.YAMLMatcher(*configFile) {
+			doc, err := swag.BytesToYAMLDoc(data)
+			if err != nil {
+				return nil, errors.Wrap(err, "parsing config file")
+			}
+			if data, err = swag.YAMLToJSON(doc); err != nil {
+				return nil, errors.Wrap(err, "parsing config file")
+			}
+		}
+		if err = json.Unmarshal(data, &opts); err != nil {
+			return nil, errors.Wrap(err, "parsing config file")
+		}
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "spec":
+			opts.Specs = specs
+		case "mapping":
+			opts.Mapping = *mapping
+		case "name":
+			opts.Name = *name
+		case "display-name":
+			opts.DisplayName = *displayName
+		case "provider-dir":
+			opts.ProviderDir = *providerDir
+		case "sdk-dir":
+			opts.SdkDir = *sdkDir
+		case "languages":
+			opts.Languages = languages
+		case "version":
+			opts.Version = *version
+		case "report":
+			opts.Reports = reports
+		}
+	})
+
+	switch positional := flags.Args(); len(positional) {
+	case 0:
+	case 2:
+		opts.SdkDir, opts.Version = positional[0], positional[1]
+	default:
+		flags.Usage()
+		return nil, errors.New("expected a target SDK folder and a version")
+	}
+
+	if len(opts.Specs) == 0 {
+		opts.Specs = []string{path.Join("open-api-spec", "todo-backend.json")}
+	}
+	if opts.Name == "" {
+		opts.Name = "xyz"
+	}
+	if opts.ProviderDir == "" {
+		opts.ProviderDir = path.Join("cmd", "pulumi-resource-"+opts.Name)
+	}
+	if opts.SdkDir == "" {
+		opts.SdkDir = "sdk"
+	}
+	if len(opts.Languages) == 0 {
+		opts.Languages = supportedLanguages
+	}
+	for _, language := range opts.Languages {
+		supported := false
+		for _, l := range supportedLanguages {
+			supported = supported || l == language
+		}
+		if !supported {
+			return nil, errors.Errorf("unsupported language '%s'", language)
+		}
+	}
+	if opts.Version == "" {
+		return nil, errors.New("missing the package version")
+	}
+	return &opts, nil
+}
+
 // emitPackage emits an entire package pack into the configured output directory with the configured settings.
-func emitPackage(targetSdkFolder, version string) error {
-	spec, metadata, err := gen.Schema()
+func emitPackage(opts *options) error {
+	spec, metadata, report, err := gen.Schema(gen.Options{
+		Specs:       opts.Specs,
+		Mapping:     opts.Mapping,
+		Name:        opts.Name,
+		DisplayName: opts.DisplayName,
+	})
 	if err != nil {
 		return errors.Wrap(err, "generating schema")
 	}
 
-	outdir := path.Join(".", "cmd", "pulumi-resource-xyz")
-	err = emitSchema(spec, version, outdir, "main")
+	// Summarize the coverage of the spec, and list the operations that didn't make it into the package.
+	if err = report.WriteText(os.Stdout, true /*skippedOnly*/); err != nil {
+		return errors.Wrap(err, "writing report")
+	}
+	for _, file := range opts.Reports {
+		if err = emitReport(report, file); err != nil {
+			return errors.Wrapf(err, "writing report to %s", file)
+		}
+	}
+
+	err = emitSchema(spec, opts.Version, opts.ProviderDir, "main")
 	if err != nil {
 		return errors.Wrap(err, "writing schema")
 	}
-	err = emitMetadata(metadata, outdir, "main")
+	err = emitMetadata(metadata, opts.ProviderDir, "main")
 	if err != nil {
-		return errors.Wrap(err, "writing schema")
+		return errors.Wrap(err, "writing metadata")
 	}
 
 	ppkg, err := pschema.ImportSpec(*spec, nil)
@@ -89,14 +261,16 @@ func emitPackage(targetSdkFolder, version string) error {
 		},
 	}
 
-	for sdkName, generator := range sdkGenerators {
-		files, err := generator()
+	languages := append([]string{}, opts.Languages...)
+	sort.Strings(languages)
+	for _, sdkName := range languages {
+		files, err := sdkGenerators[sdkName]()
 		if err != nil {
 			return errors.Wrapf(err, "generating %s package", sdkName)
 		}
 
 		for f, contents := range files {
-			if err := emitFile(path.Join(targetSdkFolder, sdkName), f, contents); err != nil {
+			if err := emitFile(path.Join(opts.SdkDir, sdkName), f, contents); err != nil {
 				return errors.Wrapf(err, "emitting file %v", f)
 			}
 		}
@@ -163,6 +337,21 @@ var apiResources = %#v
 	return emitFile(outDir, "metadata.json", formatted)
 }
 
+// emitReport writes the coverage report to a file, in JSON for files with a `.json` extension, and as text otherwise.
+func emitReport(report *gen.Report, file string) error {
+	var contents bytes.Buffer
+	if path.Ext(file) == ".json" {
+		formatted, err := json.MarshalIndent(report, "", "    ")
+		if err != nil {
+			return errors.Wrap(err, "marshaling report")
+		}
+		contents.Write(formatted)
+	} else if err := report.WriteText(&contents, false /*skippedOnly*/); err != nil {
+		return err
+	}
+	return emitFile(path.Dir(file), path.Base(file), contents.Bytes())
+}
+
 func emitFile(outDir, relPath string, contents []byte) error {
 	p := path.Join(outDir, relPath)
 	if err := tools.EnsureDir(path.Dir(p)); err != nil {
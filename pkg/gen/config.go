@@ -0,0 +1,109 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-xyz/pkg/provider"
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+// genConfig populates the provider configuration of the package. Every configuration variable is also an input of
+// the provider resource, so that explicit providers can be configured in programs, and falls back to an
+// environment variable named after the package. The SDKs only read environment variables of primitive types; the
+// provider reads the others, which hold JSON, itself.
+func (g *packageGenerator) genConfig(baseUrl string) {
+	stringType := pschema.TypeSpec{Type: "string"}
+	variables := map[string]pschema.PropertySpec{
+		"baseUrl": {
+			Description: fmt.Sprintf("The base URL of the API. Defaults to `%s`.", baseUrl),
+			TypeSpec:    stringType,
+		},
+		"headers": {
+			Description: fmt.Sprintf("Extra HTTP headers to send with every API request. Can also be set as a JSON "+
+				"object in the `%s` environment variable.", provider.EnvVarName(g.pkg.Name, "headers")),
+			TypeSpec: pschema.TypeSpec{
+				Type:                 "object",
+				AdditionalProperties: &stringType,
+			},
+		},
+		"token": {
+			Description: "A bearer token to send in the Authorization header of every API request.",
+			TypeSpec:    stringType,
+			Secret:      true,
+		},
+		"insecureSkipVerify": {
+			Description: "Disable verification of the API server's TLS certificate.",
+			TypeSpec:    pschema.TypeSpec{Type: "boolean"},
+		},
+		"caCertificate": {
+			Description: "A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.",
+			TypeSpec:    stringType,
+		},
+		"clientCertificate": {
+			Description: "A PEM-encoded client certificate for mutual TLS authentication.",
+			TypeSpec:    stringType,
+		},
+		"clientKey": {
+			Description: "The PEM-encoded private key of the client certificate.",
+			TypeSpec:    stringType,
+			Secret:      true,
+		},
+		"maxRetries": {
+			Description: "The maximum number of times a request that failed with a transient error is retried. " +
+				"Defaults to 3.",
+			TypeSpec: pschema.TypeSpec{Type: "integer"},
+			// Numeric defaults are float64, like numbers decoded from JSON.
+			Default: float64(3),
+		},
+		"idempotencyKeyHeader": {
+			Description: "The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. " +
+				"When set, POST requests are retried as well, since the API can detect repeated attempts.",
+			TypeSpec: stringType,
+		},
+	}
+
+	for name, variable := range variables {
+		if isPrimitive(&variable.TypeSpec) {
+			variable.DefaultInfo = &pschema.DefaultSpec{
+				Environment: []string{provider.EnvVarName(g.pkg.Name, name)},
+			}
+		}
+		g.addConfigVariable(name, variable)
+	}
+}
+
+// addConfigVariable adds a variable to the package configuration and the inputs of the provider resource.
+func (g *packageGenerator) addConfigVariable(name string, variable pschema.PropertySpec) {
+	if g.pkg.Config.Variables == nil {
+		g.pkg.Config.Variables = map[string]pschema.PropertySpec{}
+	}
+	if g.pkg.Provider.InputProperties == nil {
+		g.pkg.Provider.InputProperties = map[string]pschema.PropertySpec{}
+	}
+	g.pkg.Config.Variables[name] = variable
+	g.pkg.Provider.InputProperties[name] = variable
+}
+
+// isPrimitive returns true if values of the type are strings, numbers, or booleans, which the SDKs can read from
+// environment variables.
+func isPrimitive(typ *pschema.TypeSpec) bool {
+	switch typ.Type {
+	case "string", "integer", "number", "boolean":
+		return typ.Ref == ""
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-xyz/pkg/provider"
+	"github.com/pulumi/pulumi/pkg/v3/codegen"
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+// genFunctions generates data source functions from the read operations of a resource: `get<Resource>` from the
+// `Resource_Get` operation and `list<Resources>` from the `Resource_List` operation. Functions that fail to generate
+// are skipped and reported.
+func (g *packageGenerator) genFunctions(tok string, ops map[string]*operation) {
+	module, name := tok[:strings.LastIndex(tok, ":")], tok[strings.LastIndex(tok, ":")+1:]
+
+	for action, fnTok := range map[string]string{
+		"Get":  fmt.Sprintf("%s:get%s", module, name),
+		"List": fmt.Sprintf("%s:list%s", module, pluralize(name)),
+	} {
+		op, ok := ops[action]
+		switch {
+		case !ok:
+		case op.method != "GET":
+			op.skip("function '%s' requires a GET operation", fnTok)
+		default:
+			if err := g.genFunction(fnTok, name, op); err != nil {
+				op.skip("failed to generate '%s': %s", fnTok, err.Error())
+				continue
+			}
+			op.function = fnTok
+		}
+	}
+}
+
+// genFunction generates a function that invokes a GET operation. The path and query parameters of the operation
+// become the function arguments, named like the properties of resources, and the response becomes its result. An
+// array response is returned in the `items` property of the result. Inline objects in the response become types
+// named after the resource.
+func (g *packageGenerator) genFunction(tok, name string, op *operation) error {
+	inputs := pschema.ObjectTypeSpec{
+		Type:       "object",
+		Properties: map[string]pschema.PropertySpec{},
+	}
+	required := codegen.NewStringSet()
+	meta := provider.FunctionMetadata{Path: op.path, Security: securityRequirements(op.Security)}
+
+	for _, p := range op.Parameters {
+		param, err := g.resolveParameter(p)
+		if err != nil {
+			return err
+		}
+		if param.In != "path" && param.In != "query" {
+			if param.Required {
+				return errors.Errorf("unsupported parameter location '%s' of the required parameter '%s'",
+					param.In, param.Name)
+			}
+			op.warn("the optional %s parameter '%s' is ignored", param.In, param.Name)
+			continue
+		}
+
+		arg := camelCase(param.Name)
+		if _, ok := inputs.Properties[arg]; ok {
+			return errors.Errorf("the %s parameter '%s' conflicts with another argument named '%s'", param.In,
+				param.Name, arg)
+		}
+		meta.Parameters = append(meta.Parameters, provider.ParameterMetadata{
+			Name:     param.Name,
+			In:       param.In,
+			Property: arg,
+			Required: param.Required,
+		})
+		inputs.Properties[arg] = pschema.PropertySpec{
+			Description: param.Description,
+			TypeSpec:    parameterType(param),
+		}
+		if param.Required {
+			required.Add(arg)
+		}
+	}
+	inputs.Required = required.SortedValues()
+
+	schema, err := g.getResponseSchema(op.Responses.StatusCodeResponses)
+	if err != nil {
+		return err
+	}
+	resolved, err := g.resolveSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	outputs := pschema.ObjectTypeSpec{Type: "object"}
+	if resolved.Type.Contains("array") {
+		itemsType, err := g.genTypeSpec(name, schema, true /*isOutput*/)
+		if err != nil {
+			return err
+		}
+		outputs.Properties = map[string]pschema.PropertySpec{
+			"items": {TypeSpec: itemsType},
+		}
+		outputs.Required = []string{"items"}
+		meta.ItemsProperty = "items"
+	} else {
+		response, err := g.genProperties(name, resolved, true /*isOutput*/, false /*isResource*/)
+		if err != nil {
+			return err
+		}
+		outputs.Properties = response.props
+		outputs.Required = response.required.SortedValues()
+	}
+
+	g.pkg.Functions[tok] = pschema.FunctionSpec{
+		Description: op.Summary,
+		Inputs:      &inputs,
+		Outputs:     &outputs,
+	}
+	g.metadata.Functions[tok] = meta
+	return nil
+}
+
+// pluralize returns the plural form of a resource name, e.g. `Todos` for `Todo` or `Policies` for `Policy`.
+func pluralize(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && !strings.HasSuffix(name, "ay") && !strings.HasSuffix(name, "ey") &&
+		!strings.HasSuffix(name, "oy"):
+		return strings.TrimSuffix(name, "y") + "ies"
+	case strings.HasSuffix(name, "s") || strings.HasSuffix(name, "x") || strings.HasSuffix(name, "ch") ||
+		strings.HasSuffix(name, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
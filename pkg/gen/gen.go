@@ -24,22 +24,37 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/codegen"
 	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
-	"os"
-	"path/filepath"
+	"net/http"
+	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 )
 
+// Options configures the generation of a package.
+type Options struct {
+	// Mapping is the path or URL of an optional mapping file that declares the operations of resources.
+	Mapping string
+	// Specs are the paths or URLs of the Open API documents, in JSON or YAML. Multiple documents are merged into a
+	// single package; the API endpoint is taken from the first one.
+	Specs []string
+	// Name is the name of the Pulumi package, which prefixes all tokens and environment variables.
+	Name string
+	// DisplayName is the human-readable name of the API, which is used to describe the package.
+	DisplayName string
+}
+
 // Schema builds the Pulumi schema from an Open API spec. It also returns extra metadata that is not included in
-// the schema but is crucial for the provider at runtime (e.g., API endpoints).
-func Schema() (*pschema.PackageSpec, *provider.APIMetadata, error) {
-	swagger, err := loadSwaggerSpec()
+// the schema but is crucial for the provider at runtime (e.g., API endpoints), and a report of how the operations of
+// the spec were mapped to the schema.
+func Schema(opts Options) (*pschema.PackageSpec, *provider.APIMetadata, *Report, error) {
+	swagger, err := loadSwaggerSpecs(opts.Specs)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	pkg := pschema.PackageSpec{
-		Name: "xyz",
+		Name: opts.Name,
 		Language: map[string]json.RawMessage{
 			"nodejs": rawMessage(map[string]interface{}{
 				"dependencies": map[string]string{
@@ -61,75 +76,231 @@ func Schema() (*pschema.PackageSpec, *provider.APIMetadata, error) {
 		Resources: map[string]pschema.ResourceSpec{},
 		Functions: map[string]pschema.FunctionSpec{},
 	}
+	if opts.DisplayName != "" {
+		pkg.Description = fmt.Sprintf("A Pulumi package for creating and managing %s resources.", opts.DisplayName)
+	}
 	metadata := provider.APIMetadata{
-		BaseUrl:      fmt.Sprintf("%s://%s%s", swagger.Schemes[0], swagger.Host, swagger.BasePath),
+		BaseUrl:      baseUrl(swagger),
 		ResourceUrls: map[string]string{},
+		Resources:    map[string]provider.ResourceMetadata{},
+		Security:     map[string]provider.SecurityScheme{},
+		Functions:    map[string]provider.FunctionMetadata{},
 	}
 
-	// Discover all API paths and build a map of resources and resource operations.
-	resourceMap := map[string]map[string]*spec.Operation{}
-	for path, pathItem := range swagger.Paths.Paths {
-		// We expect POST, GET, PATCH, and DELETE to be present for each resource.
-		// You may need to adjust this for the resource model of your API.
-		for idx, op := range []*spec.Operation{pathItem.Post, pathItem.Get, pathItem.Patch, pathItem.Delete} {
-			if op == nil {
-				continue
-			}
+	var m *mapping
+	if opts.Mapping != "" {
+		if m, err = loadMapping(opts.Mapping); err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "loading mapping '%s'", opts.Mapping)
+		}
+	}
 
-			// Operation ID is expected to be of the shape `Resource_Action`.
-			parts := strings.Split(op.OperationProps.ID, "_")
-			if len(parts) != 2 {
-				continue
-			}
+	g := packageGenerator{pkg: &pkg, metadata: &metadata, swagger: swagger}
+	g.genConfig(metadata.BaseUrl)
+	if err = g.genSecurity(swagger.SecurityDefinitions); err != nil {
+		return nil, nil, nil, err
+	}
 
-			tok := fmt.Sprintf("%s:index:%s", pkg.Name, parts[0])
-			action := parts[1]
-			if v, ok := resourceMap[tok]; ok {
-				v[action] = op
-			} else {
-				resourceMap[tok] = map[string]*spec.Operation{
-					action: op,
-				}
-			}
+	// Discover all API operations and build a map of resources and resource operations.
+	resourceMap, settings, err := g.discoverResources(m)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-			if idx == 0 {
-				// Populate the resource creation URL for the provider metadata.
-				metadata.ResourceUrls[tok] = path
+	var toks []string
+	for tok := range resourceMap {
+		toks = append(toks, tok)
+	}
+	sort.Strings(toks)
+
+	// A resource that fails to generate is skipped and reported, so that the rest of the API is still usable.
+	for _, tok := range toks {
+		res := resourceMap[tok]
+		for action, op := range res {
+			if _, ok := resourceActions[action]; !ok {
+				op.skip("unsupported action '%s' of resource '%s'", action, tok)
 			}
 		}
-	}
 
-	g := packageGenerator{pkg: &pkg, swagger: swagger}
-	for tok, res := range resourceMap {
-		create, hasCreate := res["Create"]
-		get, hasGet := res["Get"]
-		_, hasUpdate := res["Update"]
-		_, hasDelete := res["Delete"]
-		if hasCreate && hasGet && hasUpdate && hasDelete {
-			err = g.genResources(tok, create, get)
-			if err != nil {
-				return nil, nil, err
+		// Read, update, and delete operations are optional, but a resource can't exist without a way to create it.
+		if _, ok := res["Create"]; !ok {
+			for _, op := range res {
+				op.skip("resource '%s' lacks the create operation", tok)
+			}
+		} else {
+			types := codegen.NewStringSet()
+			for typeTok := range g.pkg.Types {
+				types.Add(typeTok)
+			}
+			if err = g.genResources(tok, res, settings[tok]); err != nil {
+				for _, op := range res {
+					op.skip("%s", err.Error())
+				}
+				// Drop the types that were only generated for the failed resource.
+				for typeTok := range g.pkg.Types {
+					if !types.Has(typeTok) {
+						delete(g.pkg.Types, typeTok)
+					}
+				}
 			}
 		}
+
+		g.genFunctions(tok, res)
 	}
 
-	return &pkg, &metadata, nil
+	return &pkg, &metadata, reportOperations(g.operations), nil
 }
 
 type packageGenerator struct {
-	pkg     *pschema.PackageSpec
-	swagger *spec.Swagger
+	pkg      *pschema.PackageSpec
+	metadata *provider.APIMetadata
+	swagger  *spec.Swagger
+
+	// operations are all the operations of the spec, sorted by path and method.
+	operations []*operation
 }
 
-func (g *packageGenerator) genResources(tok string, create, get *spec.Operation) error {
-	resourceRequest, err := g.getBodyProperties(create.Parameters)
+// operation is an API operation along with the path and HTTP method it is served at, and what it was mapped to.
+type operation struct {
+	*spec.Operation
+	method string
+	path   string
+
+	resource string
+	action   string
+	function string
+	reasons  []string
+	warnings []string
+}
+
+// resourceActions maps the actions of resource operations to the names of the lifecycle steps they implement.
+var resourceActions = map[string]string{
+	"Create": "create",
+	"Get":    "read",
+	"Update": "update",
+	"Delete": "delete",
+	"List":   "list",
+}
+
+// actionMethods are the HTTP methods that the provider can send for the operations of a resource.
+var actionMethods = map[string][]string{
+	"Create": {"POST"},
+	"Get":    {"GET"},
+	"Update": {"PATCH", "PUT"},
+	"Delete": {"DELETE"},
+}
+
+// hasMethod returns true if the method is one of the HTTP methods that the provider can send for an action.
+func hasMethod(action, method string) bool {
+	for _, m := range actionMethods[action] {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// genResources generates a resource from its operations. Only the create operation is required: a resource without
+// a read operation keeps the state it was created with, a resource without an update operation is replaced on every
+// change, and a resource without a delete operation is only removed from the Pulumi state.
+func (g *packageGenerator) genResources(tok string, ops map[string]*operation, settings resourceSettings) error {
+	create, get, update, del := ops["Create"], ops["Get"], ops["Update"], ops["Delete"]
+
+	for action, methods := range actionMethods {
+		if op := ops[action]; op != nil && !hasMethod(action, op.method) {
+			return errors.Errorf("failed to generate '%s': expected a %s operation for %s, got '%s %s'", tok,
+				strings.Join(methods, " or "), strings.ToLower(action), op.method, op.path)
+		}
+	}
+	// An existing resource is identified by the path of its read operation, or of the first of its update and delete
+	// operations if it can't be read.
+	var resourcePath string
+	for _, op := range []*operation{get, update, del} {
+		if op != nil && resourcePath == "" {
+			resourcePath = op.path
+		}
+	}
+	idProperty := settings.idProperty
+	if idProperty == "" {
+		idProperty = "id"
+	}
+	name := tok[strings.LastIndex(tok, ":")+1:]
+	resourceRequest, err := g.getBodyProperties(name, create, settings.pathParameters)
 	if err != nil {
 		return errors.Wrapf(err, "failed to generate '%s': request type", tok)
 	}
 
-	response, err := g.getResponseProperties(get.Responses.StatusCodeResponses)
+	// The outputs are described by the response of the read operation, or of the create operation when the resource
+	// can't be read. Without either, the outputs are the inputs that the resource was created with.
+	var response *bag
+	if get != nil {
+		response, err = g.getResponseProperties(name, get.Responses.StatusCodeResponses)
+	} else if _, schemaErr := g.getResponseSchema(create.Responses.StatusCodeResponses); schemaErr == nil {
+		response, err = g.getResponseProperties(name, create.Responses.StatusCodeResponses)
+	} else {
+		response = &bag{
+			props:    resourceRequest.props,
+			required: codegen.NewStringSet(resourceRequest.required.SortedValues()...),
+		}
+	}
 	if err != nil {
-		return errors.Wrapf(err, "failed to generate '%s': request type", tok)
+		return errors.Wrapf(err, "failed to generate '%s': response type", tok)
+	}
+
+	// The path parameters of the create operation, e.g. the ID of the parent of a nested resource, are part of the
+	// state of the resource even if the API doesn't return them.
+	for _, prop := range explicitPathParameters(create.path, settings.pathParameters) {
+		input, isInput := resourceRequest.props[prop]
+		if _, ok := response.props[prop]; !ok && isInput {
+			response.props[prop] = input
+			response.required.Add(prop)
+		}
+	}
+
+	properties := codegen.NewStringSet()
+	for prop := range response.props {
+		properties.Add(prop)
+	}
+	for prop := range resourceRequest.props {
+		properties.Add(prop)
+	}
+	pathParameters := resourcePathParameters(resourcePath, idProperty, settings.pathParameters, properties)
+
+	// The parameters of the create operation take their values from the inputs. The parameters of the other
+	// operations take their values from the state of the resource, in which the properties of the resource path are
+	// known from the resource ID.
+	inputs := codegen.NewStringSet()
+	for prop := range resourceRequest.props {
+		inputs.Add(prop)
+	}
+	known := codegen.NewStringSet(properties.SortedValues()...)
+	known.Add(idProperty)
+	for _, prop := range pathParameters {
+		known.Add(prop)
+	}
+	operations := map[string]provider.OperationMetadata{}
+	var updateStrategy string
+	for action, op := range ops {
+		if _, ok := resourceActions[action]; !ok || action == "List" {
+			continue
+		}
+		available, params := known, resourcePathParameters(op.path, idProperty, settings.pathParameters, properties)
+		if op == create {
+			available, params = inputs, explicitPathParameters(op.path, settings.pathParameters)
+		}
+		opMeta, err := g.operationMetadata(op, params, available)
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate '%s'", tok)
+		}
+		// Specs often leave out the body of the update operation, which still has to carry the new inputs.
+		if op == update && !hasBodyParameter(&opMeta) {
+			opMeta.Parameters = append(opMeta.Parameters, provider.ParameterMetadata{Name: "body", In: "body"})
+		}
+		if op == update {
+			if updateStrategy, err = g.updateStrategy(op, settings.updateStrategy, &opMeta); err != nil {
+				return errors.Wrapf(err, "failed to generate '%s'", tok)
+			}
+		}
+		operations[resourceActions[action]] = opMeta
 	}
 
 	resourceSpec := pschema.ResourceSpec{
@@ -141,40 +312,204 @@ func (g *packageGenerator) genResources(tok string, create, get *spec.Operation)
 		InputProperties: resourceRequest.props,
 		RequiredInputs:  resourceRequest.required.SortedValues(),
 	}
+	etag, err := g.supportsETags(ops)
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate '%s'", tok)
+	}
+
 	g.pkg.Resources[tok] = resourceSpec
+	meta := provider.ResourceMetadata{
+		Readable:         get != nil,
+		Updatable:        update != nil,
+		Deletable:        del != nil,
+		ReplaceOnChanges: resourceRequest.immutable.SortedValues(),
+		Constraints:      resourceRequest.constraints,
+		WriteOnly:        resourceRequest.writeOnly.SortedValues(),
+		IdProperty:       idProperty,
+		ResourcePath:     resourcePath,
+		PathParameters:   pathParameters,
+		Polling:          map[string]provider.PollingMetadata{},
+		UpdateStrategy:   updateStrategy,
+		ETag:             etag,
+		Operations:       operations,
+	}
+	if get != nil {
+		meta.ReadGoneCodes = goneStatusCodes(get.Operation)
+	}
+	if del != nil {
+		meta.DeleteGoneCodes = goneStatusCodes(del.Operation)
+	}
+	for action, op := range map[string]*operation{"create": create, "update": update, "delete": del} {
+		if op == nil {
+			continue
+		}
+		if polling, ok := longRunning(op.Operation); ok {
+			meta.Polling[action] = polling
+		}
+	}
+	g.metadata.Resources[tok] = meta
+	g.metadata.ResourceUrls[tok] = create.path
+	for _, action := range []string{"Create", "Get", "Update", "Delete"} {
+		if op := ops[action]; op != nil {
+			op.resource, op.action = tok, resourceActions[action]
+		}
+	}
 	return nil
 }
 
+// resourcePathParameters maps the parameters of a path template to the resource properties that provide their
+// values. Parameters without an explicit mapping map to properties of the same name, except for a single parameter
+// that doesn't match a property, which maps to the ID property. In `/projects/{projectId}/todos/{todoId}`, for
+// example, `projectId` maps to the property of the parent project and `todoId` to the ID of the todo.
+func resourcePathParameters(path, idProperty string, explicit map[string]string,
+	properties codegen.StringSet) map[string]string {
+	params := pathTemplateParameters(path)
+	var unmatched []string
+	for _, param := range params {
+		if explicit[param] == "" && !properties.Has(param) {
+			unmatched = append(unmatched, param)
+		}
+	}
+
+	result := map[string]string{}
+	for _, param := range params {
+		switch {
+		case explicit[param] != "":
+			result[param] = explicit[param]
+		case len(unmatched) == 1 && unmatched[0] == param:
+			result[param] = idProperty
+		default:
+			result[param] = param
+		}
+	}
+	return result
+}
+
+// explicitPathParameters maps the parameters of a path template to the resource properties that provide their
+// values, which are properties of the same name unless the mapping says otherwise.
+func explicitPathParameters(path string, explicit map[string]string) map[string]string {
+	result := map[string]string{}
+	for _, param := range pathTemplateParameters(path) {
+		result[param] = param
+		if explicit[param] != "" {
+			result[param] = explicit[param]
+		}
+	}
+	return result
+}
+
+// pathTemplateParameters returns the names of the `{parameter}` templates in a path.
+func pathTemplateParameters(path string) []string {
+	var result []string
+	for _, match := range pathParameter.FindAllStringSubmatch(path, -1) {
+		result = append(result, match[1])
+	}
+	return result
+}
+
 type bag struct {
-	props    map[string]pschema.PropertySpec
-	required codegen.StringSet
+	props       map[string]pschema.PropertySpec
+	required    codegen.StringSet
+	immutable   codegen.StringSet
+	writeOnly   codegen.StringSet
+	constraints map[string]provider.Constraints
 }
 
-func (g *packageGenerator) getBodyProperties(parameters []spec.Parameter) (*bag, error) {
-	for _, param := range parameters {
-		switch {
-		case param.In == "body":
-			ptr := param.Schema.Ref.GetPointer()
-			if ptr == nil || ptr.IsEmpty() {
-				return nil, errors.New("expected a pointer in the schema")
+// getBodyProperties returns the input properties of a resource from its create operation: the properties of the
+// body, along with the path, query, and header parameters. Path parameters, e.g. the ID of the parent of a nested
+// resource, can't change without replacing the resource. The other parameters are checked by operationMetadata.
+func (g *packageGenerator) getBodyProperties(name string, op *operation, pathParameters map[string]string) (*bag,
+	error) {
+	var body *spec.Parameter
+	var params []*spec.Parameter
+	for _, p := range op.Parameters {
+		param, err := g.resolveParameter(p)
+		if err != nil {
+			return nil, err
+		}
+		switch param.In {
+		case "body":
+			body = param
+		case "path", "query", "header":
+			if !isIfMatch(param) {
+				params = append(params, param)
 			}
-
-			value, _, err := ptr.Get(g.swagger)
-			if err != nil {
-				return nil, errors.Wrapf(err, "get pointer")
+		}
+	}
+	pathProperties := explicitPathParameters(op.path, pathParameters)
+
+	result := &bag{
+		props:       map[string]pschema.PropertySpec{},
+		required:    codegen.NewStringSet(),
+		immutable:   codegen.NewStringSet(),
+		writeOnly:   codegen.NewStringSet(),
+		constraints: map[string]provider.Constraints{},
+	}
+	if body != nil {
+		schema, err := g.resolveSchema(body.Schema)
+		if err != nil {
+			return nil, err
+		}
+		if result, err = g.genProperties(name, schema, false /*isOutput*/, true /*isResource*/); err != nil {
+			return nil, err
+		}
+	}
+	for _, param := range params {
+		prop := camelCase(param.Name)
+		if param.In == "path" {
+			if prop = pathProperties[param.Name]; prop == "" {
+				continue
 			}
-			schema := value.(spec.Schema)
-
-			return g.genProperties(&schema, false)
-		default:
-			return nil, errors.New("non-body parameters aren't supported for Create methods")
+			result.immutable.Add(prop)
+		}
+		if _, ok := result.props[prop]; ok {
+			return nil, errors.Errorf("the %s parameter '%s' conflicts with the body property '%s'", param.In,
+				param.Name, prop)
+		}
+		result.props[prop] = pschema.PropertySpec{
+			Description: param.Description,
+			TypeSpec:    parameterType(param),
+		}
+		if param.Required {
+			result.required.Add(prop)
 		}
 	}
+	return result, nil
+}
 
-	return &bag{}, nil
+// resolveParameter follows the reference of a parameter to the shared parameter that it points to.
+func (g *packageGenerator) resolveParameter(param spec.Parameter) (*spec.Parameter, error) {
+	if param.Ref.String() == "" {
+		return &param, nil
+	}
+	value, _, err := param.Ref.GetPointer().Get(g.swagger)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unresolvable reference '%s'", param.Ref.String())
+	}
+	resolved, ok := value.(spec.Parameter)
+	if !ok {
+		return nil, errors.Errorf("reference '%s' doesn't point to a parameter", param.Ref.String())
+	}
+	return &resolved, nil
 }
 
-func (g *packageGenerator) getResponseProperties(statusCodeResponses map[int]spec.Response) (*bag, error) {
+func (g *packageGenerator) getResponseProperties(name string, statusCodeResponses map[int]spec.Response) (*bag,
+	error) {
+	schema, err := g.getResponseSchema(statusCodeResponses)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err = g.resolveSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.genProperties(name, schema, true /*isOutput*/, true /*isResource*/)
+}
+
+// getResponseSchema returns the schema of the lowest 2xx response.
+func (g *packageGenerator) getResponseSchema(statusCodeResponses map[int]spec.Response) (*spec.Schema, error) {
 	var codes []int
 	for code := range statusCodeResponses {
 		if code >= 300 || code < 200 {
@@ -189,68 +524,295 @@ func (g *packageGenerator) getResponseProperties(statusCodeResponses map[int]spe
 		return nil, errors.New("no 2xx response found")
 	}
 
-	// Find the lowest 2xx response with a schema definition and derive response properties from it.
 	resp := statusCodeResponses[codes[0]]
-	ptr := resp.Schema.Ref.GetPointer()
+	if resp.Schema == nil {
+		return nil, errors.Errorf("no schema for the %d response", codes[0])
+	}
+	return resp.Schema, nil
+}
+
+// resolveSchema follows the reference of a schema to the definition that it points to.
+func (g *packageGenerator) resolveSchema(schema *spec.Schema) (*spec.Schema, error) {
+	ptr := schema.Ref.GetPointer()
 	if ptr == nil || ptr.IsEmpty() {
-		return nil, errors.New("expected a pointer in the schema")
+		return schema, nil
 	}
 
 	value, _, err := ptr.Get(g.swagger)
 	if err != nil {
-		return nil, errors.Wrapf(err, "get pointer")
+		return nil, errors.Wrapf(err, "unresolvable reference '%s'", schema.Ref.String())
 	}
-	schema := value.(spec.Schema)
-
-	return g.genProperties(&schema, true /*isOutput*/)
+	resolved, ok := value.(spec.Schema)
+	if !ok {
+		return nil, errors.Errorf("reference '%s' doesn't point to a schema", schema.Ref.String())
+	}
+	return &resolved, nil
 }
 
-func (g *packageGenerator) genProperties(schema *spec.Schema, isOutput bool) (*bag, error) {
+// genProperties converts the properties of an object schema to Pulumi properties. Inline objects nested in the
+// properties become types named after the given name and the property.
+func (g *packageGenerator) genProperties(name string, schema *spec.Schema, isOutput, isResource bool) (*bag,
+	error) {
+	if len(schema.AllOf) > 0 {
+		merged, err := g.mergeAllOf(schema)
+		if err != nil {
+			return nil, err
+		}
+		schema = merged
+	}
+
 	result := bag{
-		props:    map[string]pschema.PropertySpec{},
-		required: codegen.NewStringSet(schema.Required...),
+		props:       map[string]pschema.PropertySpec{},
+		required:    codegen.NewStringSet(schema.Required...),
+		immutable:   codegen.NewStringSet(),
+		writeOnly:   codegen.NewStringSet(),
+		constraints: map[string]provider.Constraints{},
 	}
 
-	for name, property := range schema.Properties {
+	for propName, property := range schema.Properties {
+		property := property
 		if !isOutput && property.ReadOnly {
 			// Skip read-only properties for input types.
+			result.required.Delete(propName)
 			continue
 		}
-		if isOutput && name == "id" {
+		if isOutput && isResource && propName == "id" {
 			// Every Pulumi resource has an output called ID already, no need to add it to the schema.
+			result.required.Delete(propName)
 			continue
 		}
 
-		var primitiveTypeName string
-		if len(property.Type) > 0 {
-			primitiveTypeName = property.Type[0]
+		typeSpec, err := g.genTypeSpec(propertyTypeName(name, propName), &property, isOutput)
+		if err != nil {
+			return nil, errors.Wrapf(err, "property '%s'", propName)
 		}
 		propertySpec := pschema.PropertySpec{
 			Description: property.Description,
-			TypeSpec:    pschema.TypeSpec{Type: primitiveTypeName},
+			TypeSpec:    typeSpec,
+			Secret:      isSecret(&property),
 		}
-		result.props[name] = propertySpec
+		result.props[propName] = propertySpec
 
-		if isOutput {
-			result.required.Add(name)
+		if isImmutable(&property) {
+			result.immutable.Add(propName)
+		}
+		if c := constraints(&property); !isOutput && !c.IsEmpty() {
+			result.constraints[propName] = c
+		}
+		switch {
+		case isWriteOnly(&property):
+			// The API never returns write-only properties, so they are outputs only if they were set as inputs.
+			result.writeOnly.Add(propName)
+			if isOutput {
+				result.required.Delete(propName)
+			}
+		case isOutput && !isNullable(&property):
+			result.required.Add(propName)
 		}
 	}
 
 	return &result, nil
 }
 
-func loadSwaggerSpec() (*spec.Swagger, error) {
-	dir, err := os.Getwd()
-	if err != nil {
-		return nil, err
+// pathParameter matches a `{parameter}` template in a path.
+var pathParameter = regexp.MustCompile(`{([^}/]+)}`)
+
+// isNullable returns true if the property may be null even when the API always returns it. Such properties are
+// optional outputs. OpenAPI 3 documents express this with `nullable`, which is converted to `x-nullable`.
+func isNullable(property *spec.Schema) bool {
+	nullable, ok := property.Extensions.GetBool("x-nullable")
+	return ok && nullable
+}
+
+// isImmutable returns true if the property can't be changed after the resource is created. The spec flags such
+// properties either with `x-pulumi-immutable: true` or with an `x-ms-mutability` list that doesn't include "update".
+func isImmutable(property *spec.Schema) bool {
+	if v, ok := property.Extensions.GetBool("x-pulumi-immutable"); ok && v {
+		return true
+	}
+	if mutability, ok := property.Extensions.GetStringSlice("x-ms-mutability"); ok {
+		for _, m := range mutability {
+			if m == "update" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isSecret returns true if the value of the property is sensitive, so that Pulumi encrypts it in the state. The spec
+// flags such properties with `format: password`, with the OpenAPI 3 `writeOnly` flag, or with `x-pulumi-secret: true`.
+func isSecret(property *spec.Schema) bool {
+	if property.Format == "password" {
+		return true
+	}
+	if writeOnly, ok := property.ExtraProps["writeOnly"].(bool); ok && writeOnly {
+		return true
+	}
+	secret, ok := property.Extensions.GetBool("x-pulumi-secret")
+	return ok && secret
+}
+
+// isWriteOnly returns true if the API accepts the property but never returns it, e.g. a password. The spec flags such
+// properties with the OpenAPI 3 `writeOnly` flag or with `x-pulumi-write-only: true`.
+func isWriteOnly(property *spec.Schema) bool {
+	if writeOnly, ok := property.ExtraProps["writeOnly"].(bool); ok && writeOnly {
+		return true
+	}
+	writeOnly, ok := property.Extensions.GetBool("x-pulumi-write-only")
+	return ok && writeOnly
+}
+
+// longRunning returns the polling behavior of an operation that completes asynchronously. Such operations are marked
+// with an `x-pulumi-long-running` extension, which is either `true` or an object with the `finalStateVia` and
+// `statusProperty` settings. Azure's `x-ms-long-running-operation` extension is understood as well.
+func longRunning(op *spec.Operation) (provider.PollingMetadata, bool) {
+	switch v := op.Extensions["x-pulumi-long-running"].(type) {
+	case bool:
+		return provider.PollingMetadata{}, v
+	case map[string]interface{}:
+		var polling provider.PollingMetadata
+		polling.FinalStateVia, _ = v["finalStateVia"].(string)
+		polling.StatusProperty, _ = v["statusProperty"].(string)
+		return polling, true
+	}
+
+	if lro, ok := op.Extensions.GetBool("x-ms-long-running-operation"); ok && lro {
+		var polling provider.PollingMetadata
+		if options, ok := op.Extensions["x-ms-long-running-operation-options"].(map[string]interface{}); ok {
+			polling.FinalStateVia, _ = options["final-state-via"].(string)
+		}
+		if polling.FinalStateVia == "azure-async-operation" {
+			polling.FinalStateVia = ""
+		}
+		return polling, true
 	}
+	return provider.PollingMetadata{}, false
+}
 
-	path := filepath.Join(dir, "open-api-spec/todo-backend.json")
+// goneStatusCodes returns the documented responses of an operation that indicate that the resource doesn't exist.
+// Operations that document neither fall back to both the Not Found and Gone codes.
+func goneStatusCodes(op *spec.Operation) []int {
+	gone := []int{http.StatusNotFound, http.StatusGone}
+	var codes []int
+	for _, code := range gone {
+		if _, ok := op.Responses.StatusCodeResponses[code]; ok {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return gone
+	}
+	return codes
+}
+
+// constraints extracts the validation rules of a property so that the provider can check inputs before calling the API.
+func constraints(property *spec.Schema) provider.Constraints {
+	return provider.Constraints{
+		Enum:             property.Enum,
+		MinLength:        property.MinLength,
+		MaxLength:        property.MaxLength,
+		Pattern:          property.Pattern,
+		Minimum:          property.Minimum,
+		ExclusiveMinimum: property.ExclusiveMinimum,
+		Maximum:          property.Maximum,
+		ExclusiveMaximum: property.ExclusiveMaximum,
+	}
+}
 
+// baseUrl returns the URL that the API is served at. The scheme defaults to HTTPS, and specs that don't declare a
+// host produce a relative URL that has to be completed with the `baseUrl` configuration.
+func baseUrl(swagger *spec.Swagger) string {
+	if swagger.Host == "" {
+		return swagger.BasePath
+	}
+	scheme := "https"
+	if len(swagger.Schemes) > 0 {
+		scheme = swagger.Schemes[0]
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, swagger.Host, swagger.BasePath)
+}
+
+// loadSwaggerSpecs loads the API specs and merges them into a single document. Paths and definitions may only be
+// declared once across all documents, unless the declarations are identical.
+func loadSwaggerSpecs(paths []string) (*spec.Swagger, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no Open API spec given")
+	}
+
+	var result *spec.Swagger
+	for _, path := range paths {
+		swagger, err := loadSwaggerSpec(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading '%s'", path)
+		}
+		inheritSecurity(swagger)
+		if result == nil {
+			result = swagger
+			continue
+		}
+
+		if swagger.Paths != nil {
+			if result.Paths == nil {
+				result.Paths = &spec.Paths{}
+			}
+			if result.Paths.Paths == nil {
+				result.Paths.Paths = map[string]spec.PathItem{}
+			}
+			for p, item := range swagger.Paths.Paths {
+				if _, ok := result.Paths.Paths[p]; ok {
+					return nil, errors.Errorf("path '%s' in '%s' is already declared", p, path)
+				}
+				result.Paths.Paths[p] = item
+			}
+		}
+		if result.Definitions == nil {
+			result.Definitions = spec.Definitions{}
+		}
+		for name, schema := range swagger.Definitions {
+			if existing, ok := result.Definitions[name]; ok && !reflect.DeepEqual(existing, schema) {
+				return nil, errors.Errorf("definition '%s' in '%s' conflicts with an earlier one", name, path)
+			}
+			result.Definitions[name] = schema
+		}
+		if result.SecurityDefinitions == nil {
+			result.SecurityDefinitions = spec.SecurityDefinitions{}
+		}
+		for name, scheme := range swagger.SecurityDefinitions {
+			if existing, ok := result.SecurityDefinitions[name]; ok && !reflect.DeepEqual(existing, scheme) {
+				return nil, errors.Errorf("security definition '%s' in '%s' conflicts with an earlier one", name, path)
+			}
+			result.SecurityDefinitions[name] = scheme
+		}
+	}
+	return result, nil
+}
+
+// loadSwaggerSpec loads an API spec from a file or a URL. Both Swagger 2.0 and OpenAPI 3.x documents are supported,
+// either in JSON or in YAML; OpenAPI 3.x documents are converted to Swagger 2.0, which is the model that the
+// generator works with.
+func loadSwaggerSpec(path string) (*spec.Swagger, error) {
 	bytes, err := swag.LoadFromFileOrHTTP(path)
 	if err != nil {
 		return nil, err
 	}
+	if bytes, err = toJSON(path, bytes); err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err = json.Unmarshal(bytes, &doc); err != nil {
+		return nil, errors.Wrapf(err, "parse '%s'", path)
+	}
+	if isOpenAPI3(doc) {
+		converted, err := convertOpenAPI3(doc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "convert '%s' to Swagger 2.0", path)
+		}
+		bytes = rawMessage(converted)
+	}
+
 	swagger := spec.Swagger{}
 	err = swagger.UnmarshalJSON(bytes)
 	if err != nil {
@@ -260,6 +822,21 @@ func loadSwaggerSpec() (*spec.Swagger, error) {
 	return &swagger, nil
 }
 
+// toJSON converts a YAML document to JSON. Documents are recognized as YAML by their file extension, or by their
+// contents when the extension doesn't tell, e.g. for URLs.
+func toJSON(path string, data []byte) ([]byte, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if !swag.YAMLMatcher(path) && (strings.HasPrefix(trimmed, "{") || strings.HasSuffix(path, ".json")) {
+		return data, nil
+	}
+
+	doc, err := swag.BytesToYAMLDoc(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse YAML")
+	}
+	return swag.YAMLToJSON(doc)
+}
+
 func rawMessage(v interface{}) json.RawMessage {
 	bytes, err := json.Marshal(v)
 	contract.Assert(err == nil)
@@ -0,0 +1,285 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+)
+
+// Resources are assembled from API operations in three ways, in order of precedence:
+//
+//   1. A mapping file that lists the operations of each resource.
+//   2. `x-pulumi-resource` and `x-pulumi-action` extensions on the operations in the spec.
+//   3. The `Resource_Action` convention for operation IDs, e.g. `Todo_Create`.
+//
+// A resource that is declared by the mapping file or by extensions takes all its operations from that declaration.
+
+// mapping is the contents of a mapping file.
+type mapping struct {
+	// Resources maps resource names to their declarations.
+	Resources map[string]resourceMapping `json:"resources"`
+}
+
+// resourceMapping declares the operations of a resource and how they relate to each other. Operations are referenced
+// either by their operation ID or as `METHOD /path`, e.g. `GET /todos/{todoId}`.
+type resourceMapping struct {
+	// Module is the module of the resource token. Defaults to `index`.
+	Module string `json:"module,omitempty"`
+	Create string `json:"create,omitempty"`
+	Read   string `json:"read,omitempty"`
+	Update string `json:"update,omitempty"`
+	Delete string `json:"delete,omitempty"`
+	List   string `json:"list,omitempty"`
+	// IdProperty is the response property that identifies the resource. Defaults to `id`.
+	IdProperty string `json:"idProperty,omitempty"`
+	// PathParameters maps the path parameters of the read, update, and delete operations to the resource properties
+	// that provide their values. By default, a single path parameter maps to the ID property, and other parameters
+	// map to properties of the same name.
+	PathParameters map[string]string `json:"pathParameters,omitempty"`
+	// UpdateStrategy is how the update operation sends changes: "merge-patch", "json-patch", or "replace". By default,
+	// it depends on the method and the media types of the update operation.
+	UpdateStrategy string `json:"updateStrategy,omitempty"`
+}
+
+// mappingActions maps the action names of mapping files and `x-pulumi-action` extensions to the action names of
+// the operation ID convention.
+var mappingActions = map[string]string{
+	"create": "Create",
+	"read":   "Get",
+	"update": "Update",
+	"delete": "Delete",
+	"list":   "List",
+}
+
+// resourceSettings are the properties of a resource that don't come from its operations.
+type resourceSettings struct {
+	idProperty     string
+	pathParameters map[string]string
+	updateStrategy string
+}
+
+// loadMapping loads a mapping file in JSON or YAML.
+func loadMapping(path string) (*mapping, error) {
+	bytes, err := swag.LoadFromFileOrHTTP(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes, err = toJSON(path, bytes); err != nil {
+		return nil, err
+	}
+
+	var result mapping
+	if err = json.Unmarshal(bytes, &result); err != nil {
+		return nil, errors.Wrapf(err, "parse '%s'", path)
+	}
+	return &result, nil
+}
+
+// discoverResources builds a map of resource tokens to their operations keyed by action, along with the settings of
+// the resources.
+func (g *packageGenerator) discoverResources(m *mapping) (map[string]map[string]*operation,
+	map[string]resourceSettings, error) {
+	var ops []*operation
+	for path, pathItem := range g.swagger.Paths.Paths {
+		pathItem := pathItem
+		ops = append(ops, pathOperations(path, &pathItem)...)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].path < ops[j].path || ops[i].path == ops[j].path && ops[i].method < ops[j].method
+	})
+	g.operations = ops
+
+	resources := map[string]map[string]*operation{}
+	settings := map[string]resourceSettings{}
+	add := func(tok, action string, o *operation) error {
+		if existing, ok := resources[tok][action]; ok && existing != o {
+			return errors.Errorf("resource '%s' has multiple %s operations: '%s %s' and '%s %s'", tok,
+				strings.ToLower(action), existing.method, existing.path, o.method, o.path)
+		}
+		if _, ok := resources[tok]; !ok {
+			resources[tok] = map[string]*operation{}
+		}
+		resources[tok][action] = o
+		return nil
+	}
+
+	// Resources declared in the mapping file.
+	declared := map[string]bool{}
+	if m != nil {
+		for name, r := range m.Resources {
+			tok := g.resourceToken(r.Module, name)
+			declared[name] = true
+			for key, ref := range map[string]string{
+				"create": r.Create, "read": r.Read, "update": r.Update, "delete": r.Delete, "list": r.List,
+			} {
+				if ref == "" {
+					continue
+				}
+				o, err := findOperation(ops, ref)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "resource '%s'", name)
+				}
+				if err = add(tok, mappingActions[key], o); err != nil {
+					return nil, nil, err
+				}
+			}
+			settings[tok] = resourceSettings{
+				idProperty:     r.IdProperty,
+				pathParameters: r.PathParameters,
+				updateStrategy: r.UpdateStrategy,
+			}
+		}
+	}
+
+	// Resources declared with extensions.
+	for _, o := range ops {
+		name, module, ok := resourceExtension(o.Operation)
+		if !ok || declared[name] {
+			continue
+		}
+		action, _ := o.Extensions.GetString("x-pulumi-action")
+		if action == "" {
+			return nil, nil, errors.Errorf("operation '%s %s': missing x-pulumi-action", o.method, o.path)
+		}
+		if _, ok := mappingActions[action]; !ok {
+			return nil, nil, errors.Errorf("operation '%s %s': unknown x-pulumi-action '%s'", o.method, o.path,
+				action)
+		}
+		tok := g.resourceToken(module, name)
+		if err := add(tok, mappingActions[action], o); err != nil {
+			return nil, nil, err
+		}
+		if ext, ok := o.Extensions["x-pulumi-resource"].(map[string]interface{}); ok {
+			s := settings[tok]
+			if v, ok := ext["idProperty"].(string); ok {
+				s.idProperty = v
+			}
+			if v, ok := ext["pathParameters"].(map[string]interface{}); ok {
+				s.pathParameters = map[string]string{}
+				for param, prop := range v {
+					s.pathParameters[param], _ = prop.(string)
+				}
+			}
+			if v, ok := ext["updateStrategy"].(string); ok {
+				s.updateStrategy = v
+			}
+			settings[tok] = s
+		}
+	}
+	for tok := range resources {
+		declared[tok[strings.LastIndex(tok, ":")+1:]] = true
+	}
+
+	// The operation ID convention, `Resource_Action`, for all other resources.
+	for _, o := range ops {
+		if _, _, ok := resourceExtension(o.Operation); ok {
+			continue
+		}
+		parts := strings.Split(o.ID, "_")
+		if len(parts) != 2 || declared[parts[0]] {
+			continue
+		}
+		// We expect POST, GET, PATCH or PUT, and DELETE to be present for each resource.
+		// You may need to adjust this for the resource model of your API.
+		action := parts[1]
+		if methods, ok := actionMethods[action]; ok && !hasMethod(action, o.method) {
+			o.skip("the %s operation of a resource must be a %s, not a %s", strings.ToLower(action),
+				strings.Join(methods, " or "), o.method)
+			continue
+		}
+		if err := add(g.resourceToken("", parts[0]), action, o); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return resources, settings, nil
+}
+
+// resourceToken returns the token of a resource in the given module.
+func (g *packageGenerator) resourceToken(module, name string) string {
+	if module == "" {
+		module = "index"
+	}
+	return fmt.Sprintf("%s:%s:%s", g.pkg.Name, module, name)
+}
+
+// resourceExtension returns the resource that an operation belongs to according to its `x-pulumi-resource`
+// extension. The extension is either the name of the resource or an object with the `name` and `module` of the
+// resource, as well as its `idProperty`, `pathParameters`, and `updateStrategy`.
+func resourceExtension(op *spec.Operation) (name, module string, ok bool) {
+	switch v := op.Extensions["x-pulumi-resource"].(type) {
+	case string:
+		return v, "", v != ""
+	case map[string]interface{}:
+		name, _ = v["name"].(string)
+		module, _ = v["module"].(string)
+		return name, module, name != ""
+	}
+	return "", "", false
+}
+
+// findOperation finds an operation by its ID or by its `METHOD /path` reference.
+func findOperation(ops []*operation, ref string) (*operation, error) {
+	for _, o := range ops {
+		if o.ID == ref || strings.EqualFold(fmt.Sprintf("%s %s", o.method, o.path), ref) {
+			return o, nil
+		}
+	}
+	return nil, errors.Errorf("operation '%s' not found", ref)
+}
+
+// pathOperations returns the operations of a path. The parameters that are shared by all operations of the path are
+// added to each operation, unless the operation overrides them.
+func pathOperations(path string, item *spec.PathItem) []*operation {
+	var result []*operation
+	for method, op := range map[string]*spec.Operation{
+		"GET": item.Get, "PUT": item.Put, "POST": item.Post, "DELETE": item.Delete, "OPTIONS": item.Options,
+		"HEAD": item.Head, "PATCH": item.Patch,
+	} {
+		if op == nil {
+			continue
+		}
+		if len(item.Parameters) > 0 {
+			merged := *op
+			merged.Parameters = nil
+			for _, shared := range item.Parameters {
+				if !hasParameter(op.Parameters, shared) {
+					merged.Parameters = append(merged.Parameters, shared)
+				}
+			}
+			merged.Parameters = append(merged.Parameters, op.Parameters...)
+			op = &merged
+		}
+		result = append(result, &operation{Operation: op, method: method, path: path})
+	}
+	return result
+}
+
+// hasParameter returns true if the list has a parameter with the same name and location as the given one.
+func hasParameter(params []spec.Parameter, param spec.Parameter) bool {
+	for _, p := range params {
+		if p.Ref.String() == param.Ref.String() && p.Name == param.Name && p.In == param.In {
+			return true
+		}
+	}
+	return false
+}
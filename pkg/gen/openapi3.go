@@ -0,0 +1,509 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The generator works with the Swagger 2.0 model, so OpenAPI 3.x documents are converted to an equivalent Swagger
+// document before they are parsed. The conversion only covers the parts of the spec that the generator uses.
+
+// componentRefs maps the prefixes of OpenAPI 3 schema references to their Swagger 2.0 counterparts.
+var componentRefs = map[string]string{
+	"#/components/schemas/": "#/definitions/",
+}
+
+// serverVariable matches a `{variable}` template in a server URL.
+var serverVariable = regexp.MustCompile(`{([^}]+)}`)
+
+// isOpenAPI3 returns true if the document declares an OpenAPI 3.x version.
+func isOpenAPI3(doc map[string]interface{}) bool {
+	version, _ := doc["openapi"].(string)
+	return strings.HasPrefix(version, "3.")
+}
+
+// convertOpenAPI3 converts an OpenAPI 3.x document to Swagger 2.0: servers become the host and base path, request
+// bodies become body parameters, response and schema components move to their Swagger locations, and security
+// schemes become security definitions.
+func convertOpenAPI3(doc map[string]interface{}) (map[string]interface{}, error) {
+	c := converter{doc: doc}
+	components := asMap(doc["components"])
+
+	result := map[string]interface{}{
+		"swagger": "2.0",
+		"info":    doc["info"],
+	}
+	if err := c.convertServers(result); err != nil {
+		return nil, err
+	}
+
+	paths := map[string]interface{}{}
+	for path, item := range asMap(doc["paths"]) {
+		converted, err := c.convertPathItem(asMap(item))
+		if err != nil {
+			return nil, errors.Wrapf(err, "path '%s'", path)
+		}
+		paths[path] = converted
+	}
+	result["paths"] = paths
+
+	definitions := map[string]interface{}{}
+	for name, schema := range asMap(components["schemas"]) {
+		definitions[name] = convertSchema(schema)
+	}
+	result["definitions"] = definitions
+
+	securityDefinitions := map[string]interface{}{}
+	for name, scheme := range asMap(components["securitySchemes"]) {
+		if converted, ok := convertSecurityScheme(asMap(scheme)); ok {
+			securityDefinitions[name] = converted
+		}
+	}
+	result["securityDefinitions"] = securityDefinitions
+
+	if security, ok := doc["security"]; ok {
+		result["security"] = security
+	}
+	copyExtensions(doc, result)
+	return result, nil
+}
+
+type converter struct {
+	doc map[string]interface{}
+}
+
+// convertServers derives the host, base path, and schemes from the first server of the document. Server variables
+// are replaced with their default values.
+func (c *converter) convertServers(result map[string]interface{}) error {
+	servers, _ := c.doc["servers"].([]interface{})
+	if len(servers) == 0 {
+		return nil
+	}
+	server := asMap(servers[0])
+	rawurl, _ := server["url"].(string)
+	variables := asMap(server["variables"])
+	rawurl = serverVariable.ReplaceAllStringFunc(rawurl, func(match string) string {
+		value, _ := asMap(variables[strings.Trim(match, "{}")])["default"].(string)
+		return value
+	})
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return errors.Wrapf(err, "invalid server URL '%s'", rawurl)
+	}
+	if u.Scheme != "" {
+		result["schemes"] = []interface{}{u.Scheme}
+	}
+	if u.Host != "" {
+		result["host"] = u.Host
+	}
+	if basePath := strings.TrimSuffix(u.Path, "/"); basePath != "" {
+		result["basePath"] = basePath
+	}
+	return nil
+}
+
+// convertPathItem converts the operations and the shared parameters of a path.
+func (c *converter) convertPathItem(item map[string]interface{}) (map[string]interface{}, error) {
+	item, err := c.resolve(item)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{}
+	if params, ok := item["parameters"].([]interface{}); ok {
+		converted, err := c.convertParameters(params)
+		if err != nil {
+			return nil, err
+		}
+		result["parameters"] = converted
+	}
+	for _, method := range []string{"get", "put", "post", "delete", "options", "head", "patch"} {
+		op, ok := item[method]
+		if !ok {
+			continue
+		}
+		converted, err := c.convertOperation(asMap(op))
+		if err != nil {
+			return nil, errors.Wrapf(err, "operation '%s'", method)
+		}
+		result[method] = converted
+	}
+	copyExtensions(item, result)
+	return result, nil
+}
+
+// convertOperation converts the parameters, the request body, and the responses of an operation.
+func (c *converter) convertOperation(op map[string]interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	for _, key := range []string{"operationId", "summary", "description", "tags", "deprecated", "security"} {
+		if v, ok := op[key]; ok {
+			result[key] = v
+		}
+	}
+
+	params, _ := op["parameters"].([]interface{})
+	converted, err := c.convertParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if requestBody, ok := op["requestBody"]; ok {
+		body, err := c.resolve(asMap(requestBody))
+		if err != nil {
+			return nil, err
+		}
+		content := asMap(body["content"])
+		if _, schema := jsonContent(content); schema != nil {
+			param := map[string]interface{}{
+				"name":     "body",
+				"in":       "body",
+				"required": body["required"] == true,
+				"schema":   convertSchema(schema),
+			}
+			if description, ok := body["description"]; ok {
+				param["description"] = description
+			}
+			converted = append(converted, param)
+			result["consumes"] = contentTypes(content)
+		}
+	}
+	if len(converted) > 0 {
+		result["parameters"] = converted
+	}
+
+	responses := map[string]interface{}{}
+	var produces []interface{}
+	for code, response := range asMap(op["responses"]) {
+		response, err := c.resolve(asMap(response))
+		if err != nil {
+			return nil, errors.Wrapf(err, "response '%s'", code)
+		}
+		description, _ := response["description"].(string)
+		convertedResponse := map[string]interface{}{"description": description}
+		if mediaType, schema := jsonContent(asMap(response["content"])); schema != nil {
+			convertedResponse["schema"] = convertSchema(schema)
+			if len(produces) == 0 {
+				produces = []interface{}{mediaType}
+			}
+		}
+		if headers := asMap(response["headers"]); len(headers) > 0 {
+			convertedHeaders := map[string]interface{}{}
+			for name, header := range headers {
+				header, err := c.resolve(asMap(header))
+				if err != nil {
+					return nil, errors.Wrapf(err, "header '%s' of response '%s'", name, code)
+				}
+				convertedHeaders[name] = convertHeader(header)
+			}
+			convertedResponse["headers"] = convertedHeaders
+		}
+		responses[code] = convertedResponse
+	}
+	result["responses"] = responses
+	if len(produces) > 0 {
+		result["produces"] = produces
+	}
+
+	copyExtensions(op, result)
+	return result, nil
+}
+
+// convertHeader converts a response header. Like parameters, Swagger 2.0 headers inline their type, which has to be
+// a primitive type.
+func convertHeader(header map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{"type": "string"}
+	switch typ := asMap(header["schema"])["type"]; typ {
+	case "integer", "number", "boolean":
+		result["type"] = typ
+	}
+	if description, ok := header["description"].(string); ok {
+		result["description"] = description
+	}
+	return result
+}
+
+// convertParameters converts non-body parameters. OpenAPI 3 describes their types with a schema, while Swagger 2.0
+// inlines the type into the parameter itself. Cookie parameters have no Swagger equivalent and are skipped.
+func (c *converter) convertParameters(params []interface{}) ([]interface{}, error) {
+	var result []interface{}
+	for _, p := range params {
+		param, err := c.resolve(asMap(p))
+		if err != nil {
+			return nil, err
+		}
+		if param["in"] == "cookie" {
+			continue
+		}
+
+		converted := map[string]interface{}{}
+		for _, key := range []string{"name", "in", "description", "required"} {
+			if v, ok := param[key]; ok {
+				converted[key] = v
+			}
+		}
+		schema, err := c.resolve(asMap(param["schema"]))
+		if err != nil {
+			return nil, err
+		}
+		schema = asMap(convertSchema(schema))
+		for _, key := range []string{"type", "format", "items", "enum", "default", "minimum", "maximum",
+			"exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength", "pattern"} {
+			if v, ok := schema[key]; ok {
+				converted[key] = v
+			}
+		}
+		if _, ok := converted["type"]; !ok {
+			converted["type"] = "string"
+		}
+		copyExtensions(param, converted)
+		result = append(result, converted)
+	}
+	return result, nil
+}
+
+// resolve follows a local reference to a component, such as a shared parameter or response. Swagger 2.0 doesn't
+// have all the component kinds of OpenAPI 3, so these references are inlined.
+func (c *converter) resolve(obj map[string]interface{}) (map[string]interface{}, error) {
+	for i := 0; i < 32; i++ {
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return obj, nil
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil, errors.Errorf("unsupported reference '%s'", ref)
+		}
+
+		var value interface{} = c.doc
+		for _, segment := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			segment = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("invalid reference '%s'", ref)
+			}
+			if value, ok = m[segment]; !ok {
+				return nil, errors.Errorf("invalid reference '%s'", ref)
+			}
+		}
+		obj = asMap(value)
+	}
+	return nil, errors.New("too many nested references")
+}
+
+// convertSchema converts an OpenAPI 3 schema to a Swagger 2.0 one: references to schema components are redirected
+// to definitions, `nullable` and 3.1 `null` types become `x-nullable`, `const` becomes a single-value enum, 3.1
+// numeric exclusive bounds become the boolean flags of Swagger, and a `discriminator` object becomes the name of its
+// property. Nested schemas are converted recursively.
+func convertSchema(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, value := range v {
+			switch key {
+			case "properties", "patternProperties":
+				properties := map[string]interface{}{}
+				for name, property := range asMap(value) {
+					properties[name] = convertSchema(property)
+				}
+				result[key] = properties
+			case "items", "additionalProperties", "not", "allOf", "oneOf", "anyOf":
+				result[key] = convertSchema(value)
+			default:
+				result[key] = value
+			}
+		}
+
+		if ref, ok := result["$ref"].(string); ok {
+			for prefix, replacement := range componentRefs {
+				if strings.HasPrefix(ref, prefix) {
+					result["$ref"] = replacement + strings.TrimPrefix(ref, prefix)
+				}
+			}
+		}
+		if nullable, ok := result["nullable"].(bool); ok {
+			delete(result, "nullable")
+			if nullable {
+				result["x-nullable"] = true
+			}
+		}
+		if types, ok := result["type"].([]interface{}); ok {
+			var nonNull []interface{}
+			for _, t := range types {
+				if t == "null" {
+					result["x-nullable"] = true
+				} else {
+					nonNull = append(nonNull, t)
+				}
+			}
+			switch len(nonNull) {
+			case 0:
+				delete(result, "type")
+			case 1:
+				result["type"] = nonNull[0]
+			default:
+				result["type"] = nonNull
+			}
+		}
+		if value, ok := result["const"]; ok {
+			delete(result, "const")
+			result["enum"] = []interface{}{value}
+		}
+		if discriminator, ok := result["discriminator"].(map[string]interface{}); ok {
+			delete(result, "discriminator")
+			if name, ok := discriminator["propertyName"].(string); ok {
+				result["discriminator"] = name
+			}
+		}
+		for _, bound := range []string{"Minimum", "Maximum"} {
+			exclusive := "exclusive" + bound
+			if value, ok := result[exclusive].(float64); ok {
+				result[strings.ToLower(bound)] = value
+				result[exclusive] = true
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			result[i] = convertSchema(value)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// convertSecurityScheme converts a security scheme to a Swagger 2.0 security definition. HTTP bearer authentication
+// has no Swagger equivalent; bearer tokens are always supported through the `token` configuration variable.
+func convertSecurityScheme(scheme map[string]interface{}) (map[string]interface{}, bool) {
+	result := map[string]interface{}{}
+	if description, ok := scheme["description"]; ok {
+		result["description"] = description
+	}
+
+	switch scheme["type"] {
+	case "apiKey":
+		if scheme["in"] == "cookie" {
+			return nil, false
+		}
+		result["type"] = "apiKey"
+		result["name"] = scheme["name"]
+		result["in"] = scheme["in"]
+	case "http":
+		if scheme, _ := scheme["scheme"].(string); !strings.EqualFold(scheme, "basic") {
+			return nil, false
+		}
+		result["type"] = "basic"
+	case "oauth2":
+		flows := asMap(scheme["flows"])
+		names := map[string]string{
+			"clientCredentials": "application",
+			"password":          "password",
+			"authorizationCode": "accessCode",
+			"implicit":          "implicit",
+		}
+		// Prefer the client credentials flow, which is the only one that works without user interaction.
+		name := ""
+		for _, key := range []string{"clientCredentials", "password", "authorizationCode", "implicit"} {
+			if _, ok := flows[key]; ok {
+				name = key
+				break
+			}
+		}
+		if name == "" {
+			return nil, false
+		}
+		flow := asMap(flows[name])
+		result["type"] = "oauth2"
+		result["flow"] = names[name]
+		for _, key := range []string{"authorizationUrl", "tokenUrl"} {
+			if v, ok := flow[key]; ok {
+				result[key] = v
+			}
+		}
+		result["scopes"] = asMap(flow["scopes"])
+	default:
+		return nil, false
+	}
+	return result, true
+}
+
+// copyExtensions copies the vendor extensions of an object, such as `x-pulumi-immutable`, to its converted form.
+func copyExtensions(from, to map[string]interface{}) {
+	for key, value := range from {
+		if strings.HasPrefix(key, "x-") {
+			to[key] = value
+		}
+	}
+}
+
+// jsonContent returns the JSON media type of a content map and its schema. `application/json` is preferred, but
+// media types with a `+json` suffix are accepted as well.
+func jsonContent(content map[string]interface{}) (string, interface{}) {
+	var mediaTypes []string
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Slice(mediaTypes, func(i, j int) bool {
+		iJSON, jJSON := isPlainJSON(mediaTypes[i]), isPlainJSON(mediaTypes[j])
+		if iJSON != jJSON {
+			return iJSON
+		}
+		return mediaTypes[i] < mediaTypes[j]
+	})
+
+	for _, mediaType := range mediaTypes {
+		base := strings.TrimSpace(strings.Split(mediaType, ";")[0])
+		if base == "application/json" || strings.HasSuffix(base, "+json") {
+			return mediaType, asMap(content[mediaType])["schema"]
+		}
+	}
+	return "", nil
+}
+
+// contentTypes returns the media types of a content map, sorted, so that an operation that accepts both
+// `application/json` and `application/merge-patch+json` keeps both.
+func contentTypes(content map[string]interface{}) []interface{} {
+	var mediaTypes []string
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+
+	result := make([]interface{}, len(mediaTypes))
+	for i, mediaType := range mediaTypes {
+		result[i] = mediaType
+	}
+	return result
+}
+
+// isPlainJSON returns true if the media type is `application/json`, optionally with parameters.
+func isPlainJSON(mediaType string) bool {
+	return strings.TrimSpace(strings.Split(mediaType, ";")[0]) == "application/json"
+}
+
+// asMap returns the value as a JSON object, or an empty object if it isn't one.
+func asMap(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
@@ -0,0 +1,187 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"reflect"
+	"testing"
+)
+
+type object = map[string]interface{}
+type array = []interface{}
+
+func TestConvertSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   object
+		expected object
+	}{
+		{
+			name:     "component reference",
+			schema:   object{"$ref": "#/components/schemas/Todo"},
+			expected: object{"$ref": "#/definitions/Todo"},
+		},
+		{
+			name:     "nullable",
+			schema:   object{"type": "string", "nullable": true},
+			expected: object{"type": "string", "x-nullable": true},
+		},
+		{
+			name:     "not nullable",
+			schema:   object{"type": "string", "nullable": false},
+			expected: object{"type": "string"},
+		},
+		{
+			name:     "null type",
+			schema:   object{"type": array{"integer", "null"}},
+			expected: object{"type": "integer", "x-nullable": true},
+		},
+		{
+			name:     "const",
+			schema:   object{"type": "string", "const": "v1"},
+			expected: object{"type": "string", "enum": array{"v1"}},
+		},
+		{
+			name:   "exclusive bounds",
+			schema: object{"type": "number", "exclusiveMinimum": 0.0, "exclusiveMaximum": 10.0},
+			expected: object{
+				"type":    "number",
+				"minimum": 0.0, "exclusiveMinimum": true,
+				"maximum": 10.0, "exclusiveMaximum": true,
+			},
+		},
+		{
+			name:     "discriminator",
+			schema:   object{"discriminator": object{"propertyName": "kind"}},
+			expected: object{"discriminator": "kind"},
+		},
+		{
+			name: "nested schemas",
+			schema: object{
+				"type": "object",
+				"properties": object{
+					"tags":  object{"type": "array", "items": object{"$ref": "#/components/schemas/Tag"}},
+					"owner": object{"$ref": "#/components/schemas/User", "nullable": true},
+				},
+			},
+			expected: object{
+				"type": "object",
+				"properties": object{
+					"tags":  object{"type": "array", "items": object{"$ref": "#/definitions/Tag"}},
+					"owner": object{"$ref": "#/definitions/User", "x-nullable": true},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := convertSchema(tt.schema); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("convertSchema() = %v, expected %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertSecurityScheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		scheme   object
+		expected object
+	}{
+		{
+			name:     "API key",
+			scheme:   object{"type": "apiKey", "in": "header", "name": "X-Key"},
+			expected: object{"type": "apiKey", "in": "header", "name": "X-Key"},
+		},
+		{
+			name:   "API key in a cookie",
+			scheme: object{"type": "apiKey", "in": "cookie", "name": "session"},
+		},
+		{
+			name:     "HTTP basic",
+			scheme:   object{"type": "http", "scheme": "Basic"},
+			expected: object{"type": "basic"},
+		},
+		{
+			name:   "HTTP bearer",
+			scheme: object{"type": "http", "scheme": "bearer"},
+		},
+		{
+			name: "OAuth2 client credentials",
+			scheme: object{"type": "oauth2", "flows": object{
+				"clientCredentials": object{"tokenUrl": "https://example.com/token", "scopes": object{"read": ""}},
+			}},
+			expected: object{"type": "oauth2", "flow": "application", "tokenUrl": "https://example.com/token",
+				"scopes": object{"read": ""}},
+		},
+		{
+			name: "OAuth2 client credentials preferred over other flows",
+			scheme: object{"type": "oauth2", "flows": object{
+				"authorizationCode": object{"authorizationUrl": "https://example.com/auth",
+					"tokenUrl": "https://example.com/token", "scopes": object{}},
+				"clientCredentials": object{"tokenUrl": "https://example.com/token", "scopes": object{}},
+			}},
+			expected: object{"type": "oauth2", "flow": "application", "tokenUrl": "https://example.com/token",
+				"scopes": object{}},
+		},
+		{
+			name: "OAuth2 authorization code",
+			scheme: object{"type": "oauth2", "flows": object{
+				"authorizationCode": object{"authorizationUrl": "https://example.com/auth",
+					"tokenUrl": "https://example.com/token", "scopes": object{}},
+			}},
+			expected: object{"type": "oauth2", "flow": "accessCode", "authorizationUrl": "https://example.com/auth",
+				"tokenUrl": "https://example.com/token", "scopes": object{}},
+		},
+		{
+			name:   "OpenID Connect",
+			scheme: object{"type": "openIdConnect", "openIdConnectUrl": "https://example.com/.well-known"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := convertSecurityScheme(tt.scheme)
+			if ok != (tt.expected != nil) {
+				t.Fatalf("convertSecurityScheme() converted = %v, expected %v", ok, tt.expected != nil)
+			}
+			if ok && !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("convertSecurityScheme() = %v, expected %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJSONContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  object
+		expected string
+	}{
+		{"none", object{}, ""},
+		{"plain JSON", object{"application/json": object{}}, "application/json"},
+		{"plain JSON preferred", object{"application/merge-patch+json": object{}, "application/json": object{}},
+			"application/json"},
+		{"JSON suffix", object{"application/problem+json": object{}, "text/plain": object{}},
+			"application/problem+json"},
+		{"no JSON", object{"application/xml": object{}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual, _ := jsonContent(tt.content); actual != tt.expected {
+				t.Errorf("jsonContent() = %q, expected %q", actual, tt.expected)
+			}
+		})
+	}
+}
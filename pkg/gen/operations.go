@@ -0,0 +1,225 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-xyz/pkg/provider"
+	"github.com/pulumi/pulumi/pkg/v3/codegen"
+)
+
+// operationMetadata describes an operation of a resource for the provider. Path parameters take their values from
+// the properties that pathParameters maps them to, and query and header parameters from the properties named after
+// them. The operation fails to generate if a required parameter has no value among the available properties.
+func (g *packageGenerator) operationMetadata(op *operation, pathParameters map[string]string,
+	available codegen.StringSet) (provider.OperationMetadata, error) {
+	meta := provider.OperationMetadata{
+		Method:       op.method,
+		Path:         op.path,
+		SuccessCodes: successStatusCodes(op),
+		Security:     securityRequirements(op.Security),
+	}
+
+	hasBody := false
+	for _, p := range op.Parameters {
+		param, err := g.resolveParameter(p)
+		if err != nil {
+			return meta, err
+		}
+
+		var prop string
+		switch {
+		case isIfMatch(param):
+			// The provider sends the entity tag of the resource if the API supports them.
+			continue
+		case param.In == "body":
+			hasBody = true
+		case param.In == "path":
+			prop = pathParameters[param.Name]
+		case param.In == "query" || param.In == "header":
+			prop = camelCase(param.Name)
+		default:
+			if param.Required {
+				return meta, errors.Errorf("unsupported parameter location '%s' of the required parameter '%s' in "+
+					"'%s %s'", param.In, param.Name, op.method, op.path)
+			}
+			op.warn("the optional %s parameter '%s' is ignored", param.In, param.Name)
+			continue
+		}
+		if param.In != "body" && !available.Has(prop) {
+			if param.Required {
+				return meta, errors.Errorf("the required %s parameter '%s' of '%s %s' doesn't map to a property of "+
+					"the resource", param.In, param.Name, op.method, op.path)
+			}
+			op.warn("the optional %s parameter '%s' is ignored", param.In, param.Name)
+			continue
+		}
+
+		meta.Parameters = append(meta.Parameters, provider.ParameterMetadata{
+			Name:     param.Name,
+			In:       param.In,
+			Property: prop,
+			Required: param.Required,
+		})
+	}
+
+	consumes, produces := g.mediaTypes(op)
+	if hasBody {
+		meta.RequestContentType = jsonMediaType(consumes)
+		if meta.RequestContentType == "" && len(consumes) > 0 {
+			return meta, errors.Errorf("'%s %s' doesn't accept JSON, only %s", op.method, op.path,
+				strings.Join(consumes, ", "))
+		}
+	}
+	meta.ResponseContentType = jsonMediaType(produces)
+	return meta, nil
+}
+
+// mediaTypes returns the media types that an operation consumes and produces, which default to those of the spec.
+func (g *packageGenerator) mediaTypes(op *operation) (consumes, produces []string) {
+	consumes, produces = op.Consumes, op.Produces
+	if len(consumes) == 0 {
+		consumes = g.swagger.Consumes
+	}
+	if len(produces) == 0 {
+		produces = g.swagger.Produces
+	}
+	return consumes, produces
+}
+
+// updateStrategy picks how the update operation of a resource sends changes, unless the resource declares it
+// explicitly: a PUT replaces the resource with all inputs, a PATCH that only accepts `application/json-patch+json`
+// sends a JSON patch, and any other PATCH sends a merge patch. The request of a patch has the media type of the
+// patch format if the operation accepts it.
+func (g *packageGenerator) updateStrategy(op *operation, explicit string, meta *provider.OperationMetadata) (string,
+	error) {
+	consumes, _ := g.mediaTypes(op)
+	patchTypes := map[string]string{}
+	for _, mediaType := range consumes {
+		base := strings.TrimSpace(strings.Split(mediaType, ";")[0])
+		patchTypes[base] = mediaType
+	}
+	_, acceptsJSON := patchTypes["application/json"]
+	_, acceptsMergePatch := patchTypes["application/merge-patch+json"]
+	_, acceptsJSONPatch := patchTypes["application/json-patch+json"]
+
+	strategy := explicit
+	switch {
+	case strategy != "":
+	case op.method == "PUT":
+		strategy = "replace"
+	case acceptsJSONPatch && !acceptsJSON && !acceptsMergePatch:
+		strategy = "json-patch"
+	default:
+		strategy = "merge-patch"
+	}
+
+	switch strategy {
+	case "replace":
+	case "merge-patch":
+		if acceptsMergePatch {
+			meta.RequestContentType = patchTypes["application/merge-patch+json"]
+		}
+	case "json-patch":
+		meta.RequestContentType = "application/json-patch+json"
+		if acceptsJSONPatch {
+			meta.RequestContentType = patchTypes["application/json-patch+json"]
+		}
+	default:
+		return "", errors.Errorf("unknown update strategy '%s', expected 'merge-patch', 'json-patch', or 'replace'",
+			strategy)
+	}
+	return strategy, nil
+}
+
+// supportsETags returns true if the API tracks versions of a resource with entity tags: a successful response of
+// its create, read, or update operation declares an ETag header, or its update or delete operation accepts an
+// If-Match header.
+func (g *packageGenerator) supportsETags(ops map[string]*operation) (bool, error) {
+	for _, op := range []*operation{ops["Create"], ops["Get"], ops["Update"]} {
+		if op == nil {
+			continue
+		}
+		for code, response := range op.Responses.StatusCodeResponses {
+			for name := range response.Headers {
+				if code >= 200 && code < 300 && strings.EqualFold(name, "ETag") {
+					return true, nil
+				}
+			}
+		}
+	}
+	for _, op := range []*operation{ops["Update"], ops["Delete"]} {
+		if op == nil {
+			continue
+		}
+		for _, p := range op.Parameters {
+			param, err := g.resolveParameter(p)
+			if err != nil {
+				return false, err
+			}
+			if isIfMatch(param) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// isIfMatch returns true if the parameter is the If-Match header, which the provider manages.
+func isIfMatch(param *spec.Parameter) bool {
+	return param.In == "header" && strings.EqualFold(param.Name, "If-Match")
+}
+
+// hasBodyParameter returns true if the request of an operation has a body.
+func hasBodyParameter(meta *provider.OperationMetadata) bool {
+	for _, param := range meta.Parameters {
+		if param.In == "body" {
+			return true
+		}
+	}
+	return false
+}
+
+// successStatusCodes returns the 2xx status codes of the responses of an operation.
+func successStatusCodes(op *operation) []int {
+	var codes []int
+	for code := range op.Responses.StatusCodeResponses {
+		if code >= 200 && code < 300 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+// jsonMediaType picks a JSON media type from a list, preferring `application/json` over other JSON types such as
+// `application/merge-patch+json`. The result is empty if the list has no JSON media type.
+func jsonMediaType(mediaTypes []string) string {
+	var result string
+	for _, mediaType := range mediaTypes {
+		base := strings.TrimSpace(strings.Split(mediaType, ";")[0])
+		switch {
+		case base == "application/json":
+			return mediaType
+		case result == "" && strings.HasSuffix(base, "+json"):
+			result = mediaType
+		}
+	}
+	return result
+}
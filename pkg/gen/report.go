@@ -0,0 +1,141 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Report describes how the operations of the API spec were mapped to the package, so that unsupported parts of the
+// spec can be tracked down.
+type Report struct {
+	// Operations lists every operation of the spec, sorted by path and method.
+	Operations []OperationReport `json:"operations"`
+}
+
+// OperationReport describes what an operation became in the package.
+type OperationReport struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationId string `json:"operationId,omitempty"`
+	// Resource is the token of the resource that the operation implements, if any.
+	Resource string `json:"resource,omitempty"`
+	// Action is the part of the resource lifecycle that the operation implements: create, read, update, or delete.
+	Action string `json:"action,omitempty"`
+	// Function is the token of the function that invokes the operation, if any.
+	Function string `json:"function,omitempty"`
+	// Reasons explain why the operation or its resource wasn't generated.
+	Reasons []string `json:"reasons,omitempty"`
+	// Warnings are the parts of the operation that were ignored.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Skipped returns true if the operation didn't become a part of the package.
+func (o *OperationReport) Skipped() bool {
+	return o.Resource == "" && o.Function == ""
+}
+
+// WriteText writes the report in a human-readable form: a summary followed by a line per operation, or only per
+// skipped operation.
+func (r *Report) WriteText(w io.Writer, skippedOnly bool) error {
+	var skipped int
+	resources, functions := map[string]bool{}, map[string]bool{}
+	for _, o := range r.Operations {
+		if o.Resource != "" {
+			resources[o.Resource] = true
+		}
+		if o.Function != "" {
+			functions[o.Function] = true
+		}
+		if o.Skipped() {
+			skipped++
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d of %d operations mapped to %d resources and %d functions, %d skipped.\n",
+		len(r.Operations)-skipped, len(r.Operations), len(resources), len(functions), skipped)
+	if err != nil {
+		return err
+	}
+	if skippedOnly && skipped == 0 || len(r.Operations) == 0 {
+		return nil
+	}
+	if _, err = fmt.Fprintln(w); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, o := range r.Operations {
+		if skippedOnly && !o.Skipped() {
+			continue
+		}
+
+		var result []string
+		if o.Resource != "" {
+			result = append(result, fmt.Sprintf("resource %s (%s)", o.Resource, o.Action))
+		}
+		if o.Function != "" {
+			result = append(result, fmt.Sprintf("function %s", o.Function))
+		}
+		if o.Skipped() {
+			result = append(result, "skipped")
+		}
+		notes := append([]string{}, o.Reasons...)
+		for _, warning := range o.Warnings {
+			notes = append(notes, "warning: "+warning)
+		}
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", o.Method, o.Path, o.OperationId, strings.Join(result, ", "),
+			strings.Join(notes, "; "))
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// reportOperations builds the report of the given operations.
+func reportOperations(ops []*operation) *Report {
+	report := &Report{Operations: []OperationReport{}}
+	for _, o := range ops {
+		r := OperationReport{
+			Method:      o.method,
+			Path:        o.path,
+			OperationId: o.ID,
+			Resource:    o.resource,
+			Action:      o.action,
+			Function:    o.function,
+			Reasons:     o.reasons,
+			Warnings:    o.warnings,
+		}
+		if r.Skipped() && len(r.Reasons) == 0 {
+			r.Reasons = []string{"not mapped to a resource: the operation ID doesn't follow the Resource_Action " +
+				"convention, and the operation isn't declared in a mapping file or with x-pulumi-resource"}
+		}
+		report.Operations = append(report.Operations, r)
+	}
+	return report
+}
+
+// skip records the reason why an operation or its resource wasn't generated.
+func (o *operation) skip(format string, args ...interface{}) {
+	o.reasons = append(o.reasons, fmt.Sprintf(format, args...))
+}
+
+// warn records a part of an operation that was ignored.
+func (o *operation) warn(format string, args ...interface{}) {
+	o.warnings = append(o.warnings, fmt.Sprintf(format, args...))
+}
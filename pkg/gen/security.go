@@ -0,0 +1,175 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi-xyz/pkg/provider"
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+// genSecurity translates the security definitions of the spec into provider configuration variables that hold the
+// credentials and metadata that tells the provider how to apply them to API requests. Only API keys, HTTP basic
+// authentication, and the OAuth2 client credentials flow can be used by a non-interactive provider; other schemes
+// are skipped. A scheme whose variable would replace another config variable, e.g. an API key named `token`, fails to
+// generate.
+func (g *packageGenerator) genSecurity(definitions spec.SecurityDefinitions) error {
+	var names []string
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var schemeName string
+	var conflict error
+	addVariable := func(name string, variable pschema.PropertySpec) {
+		if _, ok := g.pkg.Config.Variables[name]; ok && conflict == nil {
+			conflict = errors.Errorf("the config variable '%s' of the security scheme '%s' conflicts with another "+
+				"config variable", name, schemeName)
+		}
+		g.addConfigVariable(name, variable)
+	}
+	stringType := pschema.TypeSpec{Type: "string"}
+	variable := func(name, description string, secret bool) string {
+		addVariable(name, pschema.PropertySpec{
+			Description: description,
+			TypeSpec:    stringType,
+			Secret:      secret,
+			DefaultInfo: &pschema.DefaultSpec{
+				Environment: []string{provider.EnvVarName(g.pkg.Name, name)},
+			},
+		})
+		return name
+	}
+
+	hasBasic, hasOAuth2 := false, false
+	for _, name := range names {
+		schemeName = name
+		scheme := definitions[name]
+		switch {
+		case scheme.Type == "apiKey" && (scheme.In == "header" || scheme.In == "query"):
+			configName := variable(camelCase(name),
+				fmt.Sprintf("The API key sent in the `%s` %s parameter.", scheme.Name, scheme.In), true)
+			g.metadata.Security[name] = provider.SecurityScheme{
+				Type:     scheme.Type,
+				Name:     scheme.Name,
+				In:       scheme.In,
+				Variable: configName,
+			}
+		case scheme.Type == "basic":
+			// Every basic scheme takes the same credentials.
+			if !hasBasic {
+				hasBasic = true
+				variable("username", "The user name for HTTP basic authentication.", false)
+				variable("password", "The password for HTTP basic authentication.", true)
+			}
+			g.metadata.Security[name] = provider.SecurityScheme{Type: scheme.Type}
+		case scheme.Type == "oauth2" && scheme.Flow == "application" && !hasOAuth2:
+			hasOAuth2 = true
+			var scopes []string
+			for scope := range scheme.Scopes {
+				scopes = append(scopes, scope)
+			}
+			sort.Strings(scopes)
+
+			variable("clientId", "The OAuth2 client ID used to obtain access tokens.", false)
+			variable("clientSecret", "The OAuth2 client secret used to obtain access tokens.", true)
+			variable("tokenUrl", fmt.Sprintf("The OAuth2 token endpoint. Defaults to `%s`.", scheme.TokenURL), false)
+			addVariable("scopes", pschema.PropertySpec{
+				Description: fmt.Sprintf("The OAuth2 scopes to request. Defaults to all scopes declared by the API. "+
+					"Can also be set as a JSON array in the `%s` environment variable.",
+					provider.EnvVarName(g.pkg.Name, "scopes")),
+				TypeSpec: pschema.TypeSpec{Type: "array", Items: &stringType},
+			})
+			g.metadata.Security[name] = provider.SecurityScheme{
+				Type:     scheme.Type,
+				TokenUrl: scheme.TokenURL,
+				Scopes:   scopes,
+			}
+		}
+		if conflict != nil {
+			return conflict
+		}
+	}
+	return nil
+}
+
+// securityRequirements converts the security requirements of an operation into the names of the security schemes of
+// each requirement. An empty list, which lets the operation be called without credentials, becomes a single
+// requirement without schemes, and nil means that the spec declares no requirements.
+func securityRequirements(security []map[string][]string) [][]string {
+	if security == nil {
+		return nil
+	}
+	if len(security) == 0 {
+		return [][]string{{}}
+	}
+
+	result := make([][]string, 0, len(security))
+	for _, requirement := range security {
+		names := []string{}
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		result = append(result, names)
+	}
+	return result
+}
+
+// inheritSecurity copies the security requirements that a spec declares for all of its operations to the operations
+// that don't declare their own, so that they aren't lost when the spec is merged with others.
+func inheritSecurity(swagger *spec.Swagger) {
+	if swagger.Security == nil || swagger.Paths == nil {
+		return
+	}
+	for _, item := range swagger.Paths.Paths {
+		for _, op := range []*spec.Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head,
+			item.Patch} {
+			if op != nil && op.Security == nil {
+				op.Security = swagger.Security
+			}
+		}
+	}
+}
+
+// camelCase converts an identifier like `api_key` or `ApiKey` to `apiKey`. It also names the properties that provide
+// the values of query and header parameters, e.g. `xRequestId` for the `X-Request-Id` header.
+func camelCase(s string) string {
+	var sb strings.Builder
+	upper := false
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = sb.Len() > 0
+			continue
+		}
+		switch {
+		case sb.Len() == 0:
+			sb.WriteRune(unicode.ToLower(r))
+		case upper:
+			sb.WriteRune(unicode.ToUpper(r))
+		default:
+			sb.WriteRune(r)
+		}
+		upper = false
+	}
+	return sb.String()
+}
@@ -0,0 +1,217 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/pkg/v3/codegen"
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+// anyType is the type of values whose shape isn't described by the spec.
+var anyType = pschema.TypeSpec{Ref: "pulumi.json#/Any"}
+
+// genTypeSpec converts an Open API schema to a Pulumi type. Arrays and maps are converted recursively, and objects
+// with properties become object types in the package. Inline objects don't have a name of their own, so the type
+// is named after the given context, e.g. `TodoSettings` for the `settings` property of `Todo`.
+func (g *packageGenerator) genTypeSpec(name string, schema *spec.Schema, isOutput bool) (pschema.TypeSpec, error) {
+	ref := schema.Ref.String()
+	if ref != "" {
+		if !strings.HasPrefix(ref, "#/definitions/") {
+			return pschema.TypeSpec{}, errors.Errorf("unsupported reference '%s'", ref)
+		}
+		name = strings.TrimPrefix(ref, "#/definitions/")
+	}
+	resolved, err := g.resolveSchema(schema)
+	if err != nil {
+		return pschema.TypeSpec{}, err
+	}
+	if len(resolved.AllOf) > 0 {
+		if resolved, err = g.mergeAllOf(resolved); err != nil {
+			return pschema.TypeSpec{}, err
+		}
+	}
+
+	switch {
+	case len(resolved.OneOf) > 0 || len(resolved.AnyOf) > 0:
+		var types []pschema.TypeSpec
+		for i, option := range append(resolved.OneOf, resolved.AnyOf...) {
+			option := option
+			typ, err := g.genTypeSpec(fmt.Sprintf("%s%d", name, i), &option, isOutput)
+			if err != nil {
+				return pschema.TypeSpec{}, err
+			}
+			types = append(types, typ)
+		}
+		if len(types) == 1 {
+			return types[0], nil
+		}
+		return pschema.TypeSpec{OneOf: types}, nil
+	case resolved.Type.Contains("array"):
+		itemType := anyType
+		if resolved.Items != nil && resolved.Items.Schema != nil {
+			if itemType, err = g.genTypeSpec(name+"Item", resolved.Items.Schema, isOutput); err != nil {
+				return pschema.TypeSpec{}, err
+			}
+		}
+		return pschema.TypeSpec{Type: "array", Items: &itemType}, nil
+	case len(resolved.Properties) > 0:
+		return g.genObjectType(name, resolved, isOutput)
+	case resolved.AdditionalProperties != nil:
+		valueType := anyType
+		if additional := resolved.AdditionalProperties; additional.Schema != nil {
+			if valueType, err = g.genTypeSpec(name+"Value", additional.Schema, isOutput); err != nil {
+				return pschema.TypeSpec{}, err
+			}
+		} else if !additional.Allows {
+			return anyType, nil
+		}
+		return pschema.TypeSpec{Type: "object", AdditionalProperties: &valueType}, nil
+	case len(resolved.Type) == 0 || resolved.Type.Contains("object"):
+		return anyType, nil
+	default:
+		return pschema.TypeSpec{Type: primitiveType(resolved.Type[0])}, nil
+	}
+}
+
+// genObjectType generates an object type for a schema with properties and returns a reference to it. Read-only
+// properties only belong to outputs, so a schema that has them gets two types: the input shape and the output one
+// with a `Response` suffix. Otherwise, a single type serves both purposes.
+func (g *packageGenerator) genObjectType(name string, schema *spec.Schema, isOutput bool) (pschema.TypeSpec, error) {
+	separate := isOutput && g.hasReadOnly(schema, codegen.NewStringSet())
+	tok := fmt.Sprintf("%s:index:%s", g.pkg.Name, name)
+	if separate {
+		tok += "Response"
+	}
+	typ := pschema.TypeSpec{Ref: fmt.Sprintf("#/types/%s", tok)}
+	if _, ok := g.pkg.Types[tok]; ok {
+		return typ, nil
+	}
+
+	// Register the type before generating its properties so that recursive schemas refer to it.
+	g.pkg.Types[tok] = pschema.ComplexTypeSpec{}
+
+	props, err := g.genProperties(name, schema, separate, false /*isResource*/)
+	if err != nil {
+		return pschema.TypeSpec{}, errors.Wrapf(err, "type '%s'", tok)
+	}
+	g.pkg.Types[tok] = pschema.ComplexTypeSpec{
+		ObjectTypeSpec: pschema.ObjectTypeSpec{
+			Description: schema.Description,
+			Type:        "object",
+			Properties:  props.props,
+			Required:    props.required.SortedValues(),
+		},
+	}
+	return typ, nil
+}
+
+// mergeAllOf combines the schemas of an `allOf` composition into a single object schema.
+func (g *packageGenerator) mergeAllOf(schema *spec.Schema) (*spec.Schema, error) {
+	merged := *schema
+	merged.AllOf = nil
+	merged.Properties = map[string]spec.Schema{}
+	for name, property := range schema.Properties {
+		merged.Properties[name] = property
+	}
+	merged.Required = append([]string{}, schema.Required...)
+
+	for _, part := range schema.AllOf {
+		part := part
+		resolved, err := g.resolveSchema(&part)
+		if err != nil {
+			return nil, err
+		}
+		if len(resolved.AllOf) > 0 {
+			if resolved, err = g.mergeAllOf(resolved); err != nil {
+				return nil, err
+			}
+		}
+		for name, property := range resolved.Properties {
+			merged.Properties[name] = property
+		}
+		merged.Required = append(merged.Required, resolved.Required...)
+		if len(merged.Type) == 0 {
+			merged.Type = resolved.Type
+		}
+	}
+	return &merged, nil
+}
+
+// hasReadOnly returns true if the schema or any of the schemas nested in it has read-only properties.
+func (g *packageGenerator) hasReadOnly(schema *spec.Schema, visited codegen.StringSet) bool {
+	if ref := schema.Ref.String(); ref != "" {
+		if visited.Has(ref) {
+			return false
+		}
+		visited.Add(ref)
+		resolved, err := g.resolveSchema(schema)
+		if err != nil {
+			return false
+		}
+		schema = resolved
+	}
+
+	for _, property := range schema.Properties {
+		property := property
+		if property.ReadOnly || g.hasReadOnly(&property, visited) {
+			return true
+		}
+	}
+	for _, part := range schema.AllOf {
+		part := part
+		if g.hasReadOnly(&part, visited) {
+			return true
+		}
+	}
+	if schema.Items != nil && schema.Items.Schema != nil && g.hasReadOnly(schema.Items.Schema, visited) {
+		return true
+	}
+	if additional := schema.AdditionalProperties; additional != nil && additional.Schema != nil {
+		return g.hasReadOnly(additional.Schema, visited)
+	}
+	return false
+}
+
+// primitiveType converts an Open API primitive type to a Pulumi schema type.
+func primitiveType(typ string) string {
+	switch typ {
+	case "integer", "number", "boolean", "array":
+		return typ
+	default:
+		return "string"
+	}
+}
+
+// parameterType converts the type of a non-body parameter to a Pulumi schema type.
+func parameterType(param *spec.Parameter) pschema.TypeSpec {
+	typ := pschema.TypeSpec{Type: primitiveType(param.Type)}
+	if param.Type == "array" && param.Items != nil {
+		typ.Items = &pschema.TypeSpec{Type: primitiveType(param.Items.Type)}
+	}
+	return typ
+}
+
+// propertyTypeName returns the name of the type of an inline object nested in the given property.
+func propertyTypeName(parent, property string) string {
+	if property == "" {
+		return parent
+	}
+	return parent + strings.ToUpper(property[:1]) + property[1:]
+}
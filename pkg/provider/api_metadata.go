@@ -15,6 +15,146 @@
 package provider
 
 type APIMetadata struct {
-	BaseUrl      string            `json:"baseUrl"`
-	ResourceUrls map[string]string `json:"resourceUrls"`
+	BaseUrl      string                      `json:"baseUrl"`
+	ResourceUrls map[string]string           `json:"resourceUrls"`
+	Resources    map[string]ResourceMetadata `json:"resources"`
+	Security     map[string]SecurityScheme   `json:"security,omitempty"`
+	Functions    map[string]FunctionMetadata `json:"functions,omitempty"`
+}
+
+// FunctionMetadata describes the GET request that implements a function.
+type FunctionMetadata struct {
+	// Path is the path template of the request, e.g. "/todos/{todoId}".
+	Path string `json:"path"`
+	// Parameters are the path and query parameters of the request and the function arguments that provide their
+	// values.
+	Parameters []ParameterMetadata `json:"parameters,omitempty"`
+	// ItemsProperty is the result property that holds the elements of an array response.
+	ItemsProperty string `json:"itemsProperty,omitempty"`
+	// Security lists the security requirements of the request. See OperationMetadata.
+	Security [][]string `json:"security,omitempty"`
+}
+
+// SecurityScheme describes how the provider authenticates API requests with the credentials from its configuration.
+type SecurityScheme struct {
+	// Type is one of "apiKey", "basic", or "oauth2".
+	Type string `json:"type"`
+	// Name is the name of the header or query parameter that carries an API key.
+	Name string `json:"name,omitempty"`
+	// In is the location of an API key, either "header" or "query".
+	In string `json:"in,omitempty"`
+	// Variable is the name of the provider config variable that holds an API key.
+	Variable string `json:"variable,omitempty"`
+	// TokenUrl is the endpoint to obtain OAuth2 access tokens from with the client credentials flow.
+	TokenUrl string `json:"tokenUrl,omitempty"`
+	// Scopes are the OAuth2 scopes to request by default.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// ResourceMetadata describes the runtime behavior of a resource that can't be expressed in the Pulumi schema.
+type ResourceMetadata struct {
+	// Readable is true if the API has an operation to read the resource. Otherwise, the resource keeps the state that
+	// it was created with.
+	Readable bool `json:"readable"`
+	// Updatable is true if the API has an operation to update the resource in place. Otherwise, every change to the
+	// inputs replaces the resource.
+	Updatable bool `json:"updatable"`
+	// Deletable is true if the API has an operation to delete the resource. Otherwise, deleting the resource only
+	// removes it from the Pulumi state.
+	Deletable bool `json:"deletable"`
+	// ReplaceOnChanges lists the input properties that can only be set at creation time.
+	ReplaceOnChanges []string `json:"replaceOnChanges,omitempty"`
+	// Constraints maps input property names to the validation rules declared for them in the Open API spec.
+	Constraints map[string]Constraints `json:"constraints,omitempty"`
+	// WriteOnly lists the input properties that the API accepts but never returns, such as passwords. Their values
+	// are carried forward from the inputs and the prior state rather than read from the API.
+	WriteOnly []string `json:"writeOnly,omitempty"`
+	// IdProperty is the response property that identifies the resource. Defaults to `id`.
+	IdProperty string `json:"idProperty,omitempty"`
+	// ResourcePath is the path template that the read, update, and delete operations address the resource by, e.g.
+	// `/todos/{todoId}`.
+	ResourcePath string `json:"resourcePath,omitempty"`
+	// PathParameters maps the parameters of ResourcePath to the resource properties that provide their values.
+	PathParameters map[string]string `json:"pathParameters,omitempty"`
+	// ReadGoneCodes are the status codes with which reading the resource reports that it no longer exists.
+	ReadGoneCodes []int `json:"readGoneCodes,omitempty"`
+	// DeleteGoneCodes are the status codes with which deleting the resource reports that it no longer exists.
+	DeleteGoneCodes []int `json:"deleteGoneCodes,omitempty"`
+	// Polling describes the operations ("create", "update", or "delete") that may complete asynchronously.
+	Polling map[string]PollingMetadata `json:"polling,omitempty"`
+	// UpdateStrategy is how the update operation sends changes to the inputs: "merge-patch" sends an RFC 7396 merge
+	// patch with the changed properties, and null for the removed ones, "json-patch" sends the RFC 6902 operations
+	// that turn the old inputs into the new ones, and "replace" sends all inputs. Defaults to "replace" for a PUT and
+	// "merge-patch" otherwise.
+	UpdateStrategy string `json:"updateStrategy,omitempty"`
+	// ETag is true if the API tracks versions of the resource with entity tags. The provider keeps the ETag of the
+	// last response in the state of the resource and sends it in the If-Match header of updates and deletes, so that
+	// changes made outside of Pulumi aren't overwritten.
+	ETag bool `json:"etag,omitempty"`
+	// Operations describes the requests that implement the lifecycle of the resource, keyed by "create", "read",
+	// "update", or "delete".
+	Operations map[string]OperationMetadata `json:"operations,omitempty"`
+}
+
+// OperationMetadata describes the HTTP request that implements an operation of a resource.
+type OperationMetadata struct {
+	// Method is the HTTP method of the request, e.g. "PATCH".
+	Method string `json:"method"`
+	// Path is the path template of the request, e.g. "/todos/{todoId}".
+	Path string `json:"path"`
+	// Parameters are the parameters of the request and the resource properties that provide their values.
+	Parameters []ParameterMetadata `json:"parameters,omitempty"`
+	// SuccessCodes are the 2xx status codes that the spec documents for a successful response. The provider accepts
+	// every 2xx response, so they only document the operation.
+	SuccessCodes []int `json:"successCodes,omitempty"`
+	// RequestContentType is the media type of the request body. Defaults to "application/json".
+	RequestContentType string `json:"requestContentType,omitempty"`
+	// ResponseContentType is the media type of the response that the request asks for, if any.
+	ResponseContentType string `json:"responseContentType,omitempty"`
+	// Security lists the alternative security requirements of the request, each with the names of the security
+	// schemes whose credentials are sent together. A requirement without schemes lets the request be sent without
+	// credentials. If the spec declares no requirements, the credentials of every configured scheme are sent.
+	Security [][]string `json:"security,omitempty"`
+}
+
+// ParameterMetadata describes a parameter of a request.
+type ParameterMetadata struct {
+	// Name is the name of the parameter in the request.
+	Name string `json:"name"`
+	// In is the location of the parameter: "path", "query", "header", or "body". The body carries the input
+	// properties of the resource that no other parameter takes.
+	In string `json:"in"`
+	// Property is the resource property that provides the value of a path, query, or header parameter.
+	Property string `json:"property,omitempty"`
+	// Required is true if the request can't be sent without the parameter.
+	Required bool `json:"required,omitempty"`
+}
+
+// PollingMetadata describes how to wait for an operation that the API accepted but completes asynchronously.
+type PollingMetadata struct {
+	// FinalStateVia is where the final state of the resource is read from once the operation completes: "location"
+	// for the URL in the Location header, or "original-uri" for the resource itself. By default, it is picked based
+	// on the headers of the response.
+	FinalStateVia string `json:"finalStateVia,omitempty"`
+	// StatusProperty is the property of the resource that reports its provisioning state when there is no separate
+	// status URL to poll, e.g. "provisioningState".
+	StatusProperty string `json:"statusProperty,omitempty"`
+}
+
+// Constraints are the validation keywords of an Open API property schema that the Pulumi schema has no place for.
+type Constraints struct {
+	Enum             []interface{} `json:"enum,omitempty"`
+	MinLength        *int64        `json:"minLength,omitempty"`
+	MaxLength        *int64        `json:"maxLength,omitempty"`
+	Pattern          string        `json:"pattern,omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty"`
+	ExclusiveMinimum bool          `json:"exclusiveMinimum,omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty"`
+	ExclusiveMaximum bool          `json:"exclusiveMaximum,omitempty"`
+}
+
+// IsEmpty returns true if no validation rules are set.
+func (c *Constraints) IsEmpty() bool {
+	return len(c.Enum) == 0 && c.MinLength == nil && c.MaxLength == nil && c.Pattern == "" &&
+		c.Minimum == nil && c.Maximum == nil
 }
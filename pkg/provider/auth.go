@@ -0,0 +1,196 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// tokenExpiryDelta is how long before its actual expiry an OAuth2 access token is considered expired, so that
+// tokens aren't sent right before they expire.
+const tokenExpiryDelta = 30 * time.Second
+
+// oauth2Token caches an access token obtained with the OAuth2 client credentials flow.
+type oauth2Token struct {
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+// invalidate drops the cached token, so that the next request obtains a new one. It returns true if there was a
+// token to drop.
+func (t *oauth2Token) invalidate() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	had := t.accessToken != ""
+	t.accessToken = ""
+	return had
+}
+
+// authenticate applies the credentials of the first security requirement of an API request that the configuration
+// satisfies. The bearer token from the configuration is sent with every request. A request without declared
+// requirements gets the credentials of every configured security scheme.
+func (p *xyzProvider) authenticate(req *http.Request, security [][]string) error {
+	if p.config.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.token)
+	}
+
+	for _, name := range p.satisfiedRequirement(security) {
+		scheme, ok := p.metadata.Security[name]
+		if !ok {
+			continue
+		}
+		switch scheme.Type {
+		case "apiKey":
+			key := p.config.apiKeys[scheme.Variable]
+			if scheme.In == "query" {
+				query := req.URL.Query()
+				query.Set(scheme.Name, key)
+				req.URL.RawQuery = query.Encode()
+			} else {
+				req.Header.Set(scheme.Name, key)
+			}
+		case "basic":
+			req.SetBasicAuth(p.config.username, p.config.password)
+		case "oauth2":
+			token, err := p.oauth2AccessToken(req.Context(), &scheme)
+			if err != nil {
+				return errors.Wrapf(err, "obtaining OAuth2 access token for %q", name)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	return nil
+}
+
+// satisfiedRequirement returns the names of the security schemes of the first requirement whose credentials are all
+// configured, or nil if there is none. Without requirements, it returns every configured scheme.
+func (p *xyzProvider) satisfiedRequirement(security [][]string) []string {
+	if security == nil {
+		var names []string
+		for name := range p.metadata.Security {
+			if p.hasCredentials(name) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	for _, requirement := range security {
+		satisfied := true
+		for _, name := range requirement {
+			satisfied = satisfied && p.hasCredentials(name)
+		}
+		if satisfied {
+			return requirement
+		}
+	}
+	return nil
+}
+
+// hasCredentials returns true if the configuration holds the credentials of a security scheme. Schemes that the
+// provider can't apply itself, such as HTTP bearer authentication, are satisfied by the bearer token.
+func (p *xyzProvider) hasCredentials(name string) bool {
+	scheme, ok := p.metadata.Security[name]
+	switch {
+	case !ok:
+		return p.config.token != ""
+	case scheme.Type == "apiKey":
+		return p.config.apiKeys[scheme.Variable] != ""
+	case scheme.Type == "basic":
+		return p.config.username != ""
+	case scheme.Type == "oauth2":
+		return p.config.clientId != ""
+	default:
+		return false
+	}
+}
+
+// oauth2AccessToken returns a valid access token for the given scheme, requesting a new one from the token endpoint
+// if the cached token is missing or expired.
+func (p *xyzProvider) oauth2AccessToken(ctx context.Context, scheme *SecurityScheme) (string, error) {
+	t := p.oauth2
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.accessToken != "" && (t.expiry.IsZero() || time.Now().Before(t.expiry)) {
+		return t.accessToken, nil
+	}
+
+	tokenUrl := scheme.TokenUrl
+	if p.config.tokenUrl != "" {
+		tokenUrl = p.config.tokenUrl
+	}
+	scopes := scheme.Scopes
+	if p.config.scopes != nil {
+		scopes = p.config.scopes
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.clientId), url.QueryEscape(p.config.clientSecret))
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode >= 300 {
+		return "", errors.Errorf("token request failed with %v: %s", res.StatusCode, body)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err = json.Unmarshal(body, &token); err != nil {
+		return "", errors.Wrapf(err, "decoding token response %s", body)
+	}
+	if token.AccessToken == "" {
+		return "", errors.Errorf("token response has no access token: %s", body)
+	}
+
+	t.accessToken = token.AccessToken
+	t.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		t.expiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenExpiryDelta)
+	}
+	return t.accessToken, nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// checkProperties validates a bag of input values against the property specs and constraints of a resource and
+// returns a failure for every property that doesn't conform. Unknown values are accepted as they will only be
+// known at deployment time.
+func checkProperties(props map[string]schema.PropertySpec, required []string, constraints map[string]Constraints,
+	values resource.PropertyMap) []*rpc.CheckFailure {
+	var failures []*rpc.CheckFailure
+	fail := func(name, format string, args ...interface{}) {
+		failures = append(failures, &rpc.CheckFailure{
+			Property: name,
+			Reason:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, name := range required {
+		if v, ok := values[resource.PropertyKey(name)]; !ok || v.IsNull() {
+			fail(name, "missing required property '%s'", name)
+		}
+	}
+
+	var names []string
+	for k := range values {
+		names = append(names, string(k))
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := unwrapValue(values[resource.PropertyKey(name)])
+		if value.IsNull() || value.IsComputed() || value.IsOutput() {
+			continue
+		}
+
+		prop, ok := props[name]
+		if !ok {
+			if resource.IsInternalPropertyKey(resource.PropertyKey(name)) {
+				continue
+			}
+			fail(name, "unknown property '%s'", name)
+			continue
+		}
+
+		if reason := checkType(&prop.TypeSpec, value); reason != "" {
+			fail(name, "property '%s' %s", name, reason)
+			continue
+		}
+
+		if c, ok := constraints[name]; ok {
+			for _, reason := range checkConstraints(&c, value) {
+				fail(name, "property '%s' %s", name, reason)
+			}
+		}
+	}
+
+	return failures
+}
+
+// unwrapValue strips secret markers from a value so that the underlying value can be validated.
+func unwrapValue(v resource.PropertyValue) resource.PropertyValue {
+	for v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+	return v
+}
+
+// checkType returns a non-empty reason if the value doesn't match the primitive type of the property. References
+// to object types only require the value to be an object.
+func checkType(typ *schema.TypeSpec, v resource.PropertyValue) string {
+	if strings.HasPrefix(typ.Ref, "#/types/") && !v.IsObject() {
+		return fmt.Sprintf("must be an object, got %s", v.TypeString())
+	}
+
+	switch typ.Type {
+	case "string":
+		if !v.IsString() {
+			return fmt.Sprintf("must be a string, got %s", v.TypeString())
+		}
+	case "integer":
+		if !v.IsNumber() || v.NumberValue() != math.Trunc(v.NumberValue()) {
+			return fmt.Sprintf("must be an integer, got %s", v.TypeString())
+		}
+	case "number":
+		if !v.IsNumber() {
+			return fmt.Sprintf("must be a number, got %s", v.TypeString())
+		}
+	case "boolean":
+		if !v.IsBool() {
+			return fmt.Sprintf("must be a boolean, got %s", v.TypeString())
+		}
+	case "array":
+		if !v.IsArray() {
+			return fmt.Sprintf("must be an array, got %s", v.TypeString())
+		}
+	case "object":
+		if !v.IsObject() {
+			return fmt.Sprintf("must be an object, got %s", v.TypeString())
+		}
+	}
+	return ""
+}
+
+// checkConstraints returns the reasons why the value violates the given Open API constraints, if any.
+func checkConstraints(c *Constraints, v resource.PropertyValue) []string {
+	var reasons []string
+
+	if len(c.Enum) > 0 {
+		allowed := false
+		for _, e := range c.Enum {
+			if reflect.DeepEqual(resource.NewPropertyValue(e).V, v.V) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			reasons = append(reasons, fmt.Sprintf("must be one of %v, got %v", c.Enum, v.V))
+		}
+	}
+
+	if v.IsString() {
+		s := v.StringValue()
+		n := int64(utf8.RuneCountInString(s))
+		if c.MinLength != nil && n < *c.MinLength {
+			reasons = append(reasons, fmt.Sprintf("must be at least %d characters long", *c.MinLength))
+		}
+		if c.MaxLength != nil && n > *c.MaxLength {
+			reasons = append(reasons, fmt.Sprintf("must be at most %d characters long", *c.MaxLength))
+		}
+		if c.Pattern != "" {
+			re, err := regexp.Compile(c.Pattern)
+			if err == nil && !re.MatchString(s) {
+				reasons = append(reasons, fmt.Sprintf("must match the pattern %q", c.Pattern))
+			}
+		}
+	}
+
+	if v.IsNumber() {
+		n := v.NumberValue()
+		if c.Minimum != nil && (n < *c.Minimum || c.ExclusiveMinimum && n == *c.Minimum) {
+			if c.ExclusiveMinimum {
+				reasons = append(reasons, fmt.Sprintf("must be greater than %v", *c.Minimum))
+			} else {
+				reasons = append(reasons, fmt.Sprintf("must be greater than or equal to %v", *c.Minimum))
+			}
+		}
+		if c.Maximum != nil && (n > *c.Maximum || c.ExclusiveMaximum && n == *c.Maximum) {
+			if c.ExclusiveMaximum {
+				reasons = append(reasons, fmt.Sprintf("must be less than %v", *c.Maximum))
+			} else {
+				reasons = append(reasons, fmt.Sprintf("must be less than or equal to %v", *c.Maximum))
+			}
+		}
+	}
+
+	return reasons
+}
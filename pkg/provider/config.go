@@ -0,0 +1,296 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// providerConfig holds the provider settings that control how API requests are made.
+type providerConfig struct {
+	baseUrl            string
+	headers            map[string]string
+	token              string
+	insecureSkipVerify bool
+	caCertificate      string
+	clientCertificate  string
+	clientKey          string
+
+	// Retry policy for transient failures.
+	maxRetries           int
+	idempotencyKeyHeader string
+
+	// Credentials of the security schemes declared by the API.
+	apiKeys      map[string]string
+	username     string
+	password     string
+	clientId     string
+	clientSecret string
+	tokenUrl     string
+	scopes       []string
+}
+
+// configValues collects the provider configuration from a bag of config values keyed by variable name. Variables
+// that aren't set fall back to the environment variables declared for them in the schema. The schema only declares
+// environment variables for primitive values, so the others, such as `headers`, fall back to the environment
+// variable named after them, e.g. `XYZ_HEADERS`, which holds JSON that parseConfig decodes.
+func (p *xyzProvider) configValues(values resource.PropertyMap) resource.PropertyMap {
+	result := resource.PropertyMap{}
+	for name, variable := range p.pkgSpec.Config.Variables {
+		key := resource.PropertyKey(name)
+		if v, ok := values[key]; ok && !v.IsNull() {
+			result[key] = v
+			continue
+		}
+		envs := []string{EnvVarName(p.name, name)}
+		if variable.DefaultInfo != nil {
+			envs = variable.DefaultInfo.Environment
+		}
+		for _, env := range envs {
+			if v, ok := os.LookupEnv(env); ok {
+				result[key] = resource.NewStringProperty(v)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// parseConfig converts config values into the provider settings. Config values arrive either typed or as strings
+// (the engine passes non-string provider inputs JSON-encoded), so both representations are accepted. Unknown values
+// are skipped. Every invalid value produces a check failure.
+func parseConfig(values resource.PropertyMap,
+	security map[string]SecurityScheme) (*providerConfig, []*rpc.CheckFailure) {
+	var failures []*rpc.CheckFailure
+	fail := func(name, format string, args ...interface{}) {
+		failures = append(failures, &rpc.CheckFailure{
+			Property: name,
+			Reason:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	str := func(name string) string {
+		v := unwrapValue(values[resource.PropertyKey(name)])
+		switch {
+		case v.IsString():
+			return v.StringValue()
+		case v.IsNull(), v.IsComputed(), v.IsOutput():
+			return ""
+		default:
+			fail(name, "'%s' must be a string, got %s", name, v.TypeString())
+			return ""
+		}
+	}
+
+	config := providerConfig{
+		baseUrl:           str("baseUrl"),
+		token:             str("token"),
+		caCertificate:     str("caCertificate"),
+		clientCertificate: str("clientCertificate"),
+		clientKey:         str("clientKey"),
+		apiKeys:           map[string]string{},
+		username:          str("username"),
+		password:          str("password"),
+		clientId:          str("clientId"),
+		clientSecret:      str("clientSecret"),
+		tokenUrl:          str("tokenUrl"),
+
+		maxRetries:           defaultMaxRetries,
+		idempotencyKeyHeader: str("idempotencyKeyHeader"),
+	}
+	for _, scheme := range security {
+		if scheme.Type == "apiKey" {
+			config.apiKeys[scheme.Variable] = str(scheme.Variable)
+		}
+	}
+
+	if config.baseUrl != "" {
+		u, err := url.Parse(config.baseUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("baseUrl", "'baseUrl' must be an absolute http or https URL, got %q", config.baseUrl)
+		}
+		config.baseUrl = strings.TrimSuffix(config.baseUrl, "/")
+	}
+
+	switch v := unwrapValue(values["insecureSkipVerify"]); {
+	case v.IsBool():
+		config.insecureSkipVerify = v.BoolValue()
+	case v.IsString():
+		b, err := strconv.ParseBool(v.StringValue())
+		if err != nil {
+			fail("insecureSkipVerify", "'insecureSkipVerify' must be a boolean, got %q", v.StringValue())
+		}
+		config.insecureSkipVerify = b
+	case v.IsNull(), v.IsComputed(), v.IsOutput():
+	default:
+		fail("insecureSkipVerify", "'insecureSkipVerify' must be a boolean, got %s", v.TypeString())
+	}
+
+	switch v := unwrapValue(values["maxRetries"]); {
+	case v.IsNumber():
+		config.maxRetries = int(v.NumberValue())
+	case v.IsString():
+		n, err := strconv.Atoi(v.StringValue())
+		if err != nil {
+			fail("maxRetries", "'maxRetries' must be an integer, got %q", v.StringValue())
+		}
+		config.maxRetries = n
+	case v.IsNull(), v.IsComputed(), v.IsOutput():
+	default:
+		fail("maxRetries", "'maxRetries' must be an integer, got %s", v.TypeString())
+	}
+	if config.maxRetries < 0 {
+		fail("maxRetries", "'maxRetries' must not be negative, got %d", config.maxRetries)
+	}
+
+	switch v := unwrapValue(values["headers"]); {
+	case v.IsObject():
+		config.headers = map[string]string{}
+		for k, h := range v.ObjectValue() {
+			h = unwrapValue(h)
+			if !h.IsString() {
+				fail("headers", "header '%s' must be a string, got %s", k, h.TypeString())
+				continue
+			}
+			config.headers[string(k)] = h.StringValue()
+		}
+	case v.IsString():
+		if err := json.Unmarshal([]byte(v.StringValue()), &config.headers); err != nil {
+			fail("headers", "'headers' must be a map of strings: %v", err)
+		}
+	case v.IsNull(), v.IsComputed(), v.IsOutput():
+	default:
+		fail("headers", "'headers' must be a map of strings, got %s", v.TypeString())
+	}
+
+	switch v := unwrapValue(values["scopes"]); {
+	case v.IsArray():
+		config.scopes = []string{}
+		for _, scope := range v.ArrayValue() {
+			scope = unwrapValue(scope)
+			if !scope.IsString() {
+				fail("scopes", "'scopes' must be a list of strings, got an element of type %s", scope.TypeString())
+				continue
+			}
+			config.scopes = append(config.scopes, scope.StringValue())
+		}
+	case v.IsString():
+		if err := json.Unmarshal([]byte(v.StringValue()), &config.scopes); err != nil {
+			fail("scopes", "'scopes' must be a list of strings: %v", err)
+		}
+	case v.IsNull(), v.IsComputed(), v.IsOutput():
+	default:
+		fail("scopes", "'scopes' must be a list of strings, got %s", v.TypeString())
+	}
+
+	if config.tokenUrl != "" {
+		if u, err := url.Parse(config.tokenUrl); err != nil || !u.IsAbs() {
+			fail("tokenUrl", "'tokenUrl' must be an absolute URL, got %q", config.tokenUrl)
+		}
+	}
+	if config.clientId != "" && config.clientSecret == "" {
+		fail("clientSecret", "'clientSecret' is required when 'clientId' is set")
+	}
+	// The bearer token would be replaced by the credentials of a scheme that also uses the Authorization header.
+	switch {
+	case config.token != "" && config.clientId != "":
+		fail("token", "'token' and 'clientId' can't both be set, since both provide the Authorization header")
+	case config.token != "" && config.username != "":
+		fail("token", "'token' and 'username' can't both be set, since both provide the Authorization header")
+	}
+
+	if config.caCertificate != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(config.caCertificate)) {
+		fail("caCertificate", "'caCertificate' doesn't contain any PEM-encoded certificates")
+	}
+	switch {
+	case config.clientCertificate != "" && config.clientKey == "":
+		fail("clientKey", "'clientKey' is required when 'clientCertificate' is set")
+	case config.clientCertificate == "" && config.clientKey != "":
+		fail("clientCertificate", "'clientCertificate' is required when 'clientKey' is set")
+	case config.clientCertificate != "":
+		if _, err := tls.X509KeyPair([]byte(config.clientCertificate), []byte(config.clientKey)); err != nil {
+			fail("clientCertificate", "invalid client certificate or key: %v", err)
+		}
+	}
+
+	return &config, failures
+}
+
+// EnvVarName converts a camelCase configuration variable name into the name of its environment variable, e.g.
+// `baseUrl` becomes `XYZ_BASE_URL`.
+func EnvVarName(pkgName, name string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToUpper(pkgName))
+	sb.WriteRune('_')
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			sb.WriteRune('_')
+		}
+		sb.WriteString(strings.ToUpper(string(r)))
+	}
+	return sb.String()
+}
+
+// configVariables converts the variables of a configure request, keyed as `<package>:config:<name>`, into config
+// values keyed by variable name.
+func (p *xyzProvider) configVariables(vars map[string]string) resource.PropertyMap {
+	prefix := p.name + ":config:"
+	result := resource.PropertyMap{}
+	for k, v := range vars {
+		result[resource.PropertyKey(strings.TrimPrefix(k, prefix))] = resource.NewStringProperty(v)
+	}
+	return result
+}
+
+// httpClient builds the HTTP client that is used for all API requests with the given settings.
+func (c *providerConfig) httpClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: c.insecureSkipVerify,
+	}
+	if c.caCertificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.caCertificate)) {
+			return nil, errors.New("no PEM-encoded certificates found in 'caCertificate'")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.clientCertificate != "" {
+		cert, err := tls.X509KeyPair([]byte(c.clientCertificate), []byte(c.clientKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "loading client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
+
+// configReplaces lists the config variables whose change requires replacing all resources managed by the provider.
+// Resources created against one API endpoint can't be managed through another.
+var configReplaces = []string{"baseUrl"}
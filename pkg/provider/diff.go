@@ -0,0 +1,166 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// inputsKey is the name of a hidden output property that records the inputs that the resource was last created or
+// updated with. Diffs are computed against these inputs rather than against the API response, which may contain
+// server-side defaults.
+const inputsKey = "__inputs"
+
+// withInputs returns a copy of outputs that carries the given inputs in the hidden inputs property.
+func withInputs(outputs, inputs resource.PropertyMap) resource.PropertyMap {
+	result := outputs.Copy()
+	result[inputsKey] = resource.NewObjectProperty(inputs)
+	return result
+}
+
+// withWriteOnly returns a copy of outputs in which the write-only properties of the resource take their values from
+// the first of the given sources that has one, e.g. the inputs or the prior state. The API never returns these
+// properties, or returns placeholders such as masked passwords, so its response would otherwise show them as drifted.
+func withWriteOnly(meta *ResourceMetadata, outputs resource.PropertyMap,
+	sources ...resource.PropertyMap) resource.PropertyMap {
+	if len(meta.WriteOnly) == 0 {
+		return outputs
+	}
+
+	result := outputs.Copy()
+	for _, name := range meta.WriteOnly {
+		key := resource.PropertyKey(name)
+		delete(result, key)
+		for _, source := range sources {
+			if v, ok := source[key]; ok && !v.IsNull() {
+				result[key] = v
+				break
+			}
+		}
+	}
+	return result
+}
+
+// oldInputs returns the inputs recorded in the resource state. State written before the inputs were tracked is
+// projected onto the input properties of the resource instead.
+func oldInputs(res *schema.ResourceSpec, olds resource.PropertyMap) resource.PropertyMap {
+	if inputs, ok := olds[inputsKey]; ok && inputs.IsObject() {
+		return inputs.ObjectValue()
+	}
+	return projectInputs(res, olds)
+}
+
+// projectInputs picks the values of the input properties of a resource from its state. Read-only properties aren't
+// inputs, so they are left out.
+func projectInputs(res *schema.ResourceSpec, state resource.PropertyMap) resource.PropertyMap {
+	result := resource.PropertyMap{}
+	for name := range res.InputProperties {
+		if v, ok := state[resource.PropertyKey(name)]; ok && !v.IsNull() {
+			result[resource.PropertyKey(name)] = v
+		}
+	}
+	return result
+}
+
+// replaceOnChanges returns the names of the input properties of the given resource that can't be updated in place.
+func (p *xyzProvider) replaceOnChanges(tok string, res *schema.ResourceSpec) codegen.StringSet {
+	meta, ok := p.metadata.Resources[tok]
+	if !ok {
+		return codegen.NewStringSet()
+	}
+
+	if !meta.Updatable {
+		result := codegen.NewStringSet()
+		for name := range res.InputProperties {
+			result.Add(name)
+		}
+		return result
+	}
+	return codegen.NewStringSet(meta.ReplaceOnChanges...)
+}
+
+// ignoreChanges builds a key filter from the ignoreChanges paths of a diff request. Only top-level properties
+// can be ignored; a nested path ignores the whole top-level property it belongs to.
+func ignoreChanges(paths []string) resource.IgnoreKeyFunc {
+	ignored := codegen.NewStringSet()
+	for _, path := range paths {
+		if i := strings.IndexAny(path, ".["); i >= 0 {
+			path = path[:i]
+		}
+		ignored.Add(path)
+	}
+	return func(key resource.PropertyKey) bool {
+		return ignored.Has(string(key))
+	}
+}
+
+// detailedDiff converts an object diff of resource inputs into the engine's detailed diff representation, marking
+// changes of the given properties as requiring a replacement.
+func detailedDiff(diff *resource.ObjectDiff, replaces codegen.StringSet) map[string]*rpc.PropertyDiff {
+	result := map[string]*rpc.PropertyDiff{}
+	add := func(key resource.PropertyKey, kind, replaceKind rpc.PropertyDiff_Kind) {
+		if replaces.Has(string(key)) {
+			kind = replaceKind
+		}
+		result[string(key)] = &rpc.PropertyDiff{Kind: kind, InputDiff: true}
+	}
+
+	for k := range diff.Adds {
+		add(k, rpc.PropertyDiff_ADD, rpc.PropertyDiff_ADD_REPLACE)
+	}
+	for k := range diff.Deletes {
+		add(k, rpc.PropertyDiff_DELETE, rpc.PropertyDiff_DELETE_REPLACE)
+	}
+	for k := range diff.Updates {
+		add(k, rpc.PropertyDiff_UPDATE, rpc.PropertyDiff_UPDATE_REPLACE)
+	}
+	return result
+}
+
+// diffResponse builds the response to a diff request from an object diff of inputs. A nil diff means no changes.
+func diffResponse(diff *resource.ObjectDiff, replaceKeys codegen.StringSet) *rpc.DiffResponse {
+	if diff == nil {
+		return &rpc.DiffResponse{
+			Changes:         rpc.DiffResponse_DIFF_NONE,
+			HasDetailedDiff: true,
+		}
+	}
+
+	detailedDiff := detailedDiff(diff, replaceKeys)
+	var diffs, replaces []string
+	for name, d := range detailedDiff {
+		diffs = append(diffs, name)
+		switch d.Kind {
+		case rpc.PropertyDiff_ADD_REPLACE, rpc.PropertyDiff_DELETE_REPLACE, rpc.PropertyDiff_UPDATE_REPLACE:
+			replaces = append(replaces, name)
+		}
+	}
+	sort.Strings(diffs)
+	sort.Strings(replaces)
+
+	return &rpc.DiffResponse{
+		Changes:         rpc.DiffResponse_DIFF_SOME,
+		Diffs:           diffs,
+		Replaces:        replaces,
+		DetailedDiff:    detailedDiff,
+		HasDetailedDiff: true,
+	}
+}
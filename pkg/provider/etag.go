@@ -0,0 +1,59 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// etagKey is the name of a hidden output property that records the entity tag of the last response that the API
+// returned for the resource. Updates and deletes send it back in the If-Match header, so that the API rejects them if
+// the resource was changed in the meantime.
+const etagKey = "__etag"
+
+// withETag records the entity tag of a response in the state of a resource whose API supports them. A response
+// without an entity tag leaves none, since the one from an earlier response no longer matches the resource.
+func withETag(meta *ResourceMetadata, state map[string]interface{}, header http.Header) map[string]interface{} {
+	if !meta.ETag {
+		return state
+	}
+	delete(state, etagKey)
+	if etag := header.Get("ETag"); etag != "" {
+		state[etagKey] = etag
+	}
+	return state
+}
+
+// setIfMatch makes a request conditional on the resource being unchanged since the entity tag in its state was
+// recorded.
+func setIfMatch(meta *ResourceMetadata, r *apiRequest, state map[string]interface{}) {
+	if etag, ok := state[etagKey].(string); ok && meta.ETag && etag != "" {
+		r.header.Set("If-Match", etag)
+	}
+}
+
+// preconditionError explains a request that the API rejected because the resource was changed after its entity tag
+// was recorded.
+func preconditionError(urn resource.URN, err error) error {
+	var httpErr *httpError
+	if errors.As(err, &httpErr) && httpErr.statusCode == http.StatusPreconditionFailed {
+		return errors.Errorf("%s was changed outside of Pulumi since it was last read; run `pulumi refresh` to "+
+			"update its state, then try again", urn.Name())
+	}
+	return err
+}
@@ -0,0 +1,136 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// updateStrategy returns how a resource sends changes to the API. Metadata that doesn't declare the strategy implies
+// it from the method of the update operation.
+func updateStrategy(meta *ResourceMetadata, method string) string {
+	switch {
+	case meta.UpdateStrategy != "":
+		return meta.UpdateStrategy
+	case method == "PUT":
+		return "replace"
+	default:
+		return "merge-patch"
+	}
+}
+
+// mergePatch returns the RFC 7396 merge patch that turns olds into news. The patch has the properties whose values
+// changed, with nested objects patched recursively, and null for the properties that were removed. Arrays are
+// replaced as a whole.
+func mergePatch(olds, news map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for k, v := range news {
+		old, ok := olds[k]
+		switch {
+		case !ok:
+			patch[k] = v
+		case reflect.DeepEqual(old, v):
+		default:
+			oldObject, oldIsObject := old.(map[string]interface{})
+			newObject, newIsObject := v.(map[string]interface{})
+			if oldIsObject && newIsObject {
+				patch[k] = mergePatch(oldObject, newObject)
+			} else {
+				patch[k] = v
+			}
+		}
+	}
+	for k := range olds {
+		if _, ok := news[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	return patch
+}
+
+// jsonPatch returns the RFC 6902 JSON patch that turns olds into news. Properties are added, removed, or replaced,
+// and nested objects and arrays are patched element by element. Arrays are grown by adding elements at their end
+// and shrunk by removing elements from their end, so that the indices of the operations remain valid.
+func jsonPatch(olds, news map[string]interface{}) []interface{} {
+	ops := []interface{}{}
+	diffObjects("", olds, news, &ops)
+	return ops
+}
+
+func diffObjects(path string, olds, news map[string]interface{}, ops *[]interface{}) {
+	keys := make([]string, 0, len(olds)+len(news))
+	for k := range olds {
+		keys = append(keys, k)
+	}
+	for k := range news {
+		if _, ok := olds[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := path + "/" + escapePointer(k)
+		old, inOlds := olds[k]
+		v, inNews := news[k]
+		switch {
+		case !inNews:
+			*ops = append(*ops, map[string]interface{}{"op": "remove", "path": p})
+		case !inOlds:
+			*ops = append(*ops, map[string]interface{}{"op": "add", "path": p, "value": v})
+		default:
+			diffValues(p, old, v, ops)
+		}
+	}
+}
+
+func diffArrays(path string, olds, news []interface{}, ops *[]interface{}) {
+	for i := 0; i < len(olds) && i < len(news); i++ {
+		diffValues(path+"/"+strconv.Itoa(i), olds[i], news[i], ops)
+	}
+	for i := len(olds); i < len(news); i++ {
+		*ops = append(*ops, map[string]interface{}{"op": "add", "path": path + "/" + strconv.Itoa(i), "value": news[i]})
+	}
+	for i := len(olds) - 1; i >= len(news); i-- {
+		*ops = append(*ops, map[string]interface{}{"op": "remove", "path": path + "/" + strconv.Itoa(i)})
+	}
+}
+
+func diffValues(path string, old, v interface{}, ops *[]interface{}) {
+	if reflect.DeepEqual(old, v) {
+		return
+	}
+	oldObject, oldIsObject := old.(map[string]interface{})
+	newObject, newIsObject := v.(map[string]interface{})
+	if oldIsObject && newIsObject {
+		diffObjects(path, oldObject, newObject, ops)
+		return
+	}
+	oldArray, oldIsArray := old.([]interface{})
+	newArray, newIsArray := v.([]interface{})
+	if oldIsArray && newIsArray {
+		diffArrays(path, oldArray, newArray, ops)
+		return
+	}
+	*ops = append(*ops, map[string]interface{}{"op": "replace", "path": path, "value": v})
+}
+
+// escapePointer escapes a property name for use as a reference token of a JSON pointer.
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,187 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+type object = map[string]interface{}
+type array = []interface{}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		olds, news object
+		expected   object
+	}{
+		{
+			name:     "unchanged",
+			olds:     object{"name": "a", "tags": array{"x"}},
+			news:     object{"name": "a", "tags": array{"x"}},
+			expected: object{},
+		},
+		{
+			name:     "added, changed, and removed properties",
+			olds:     object{"name": "a", "color": "red"},
+			news:     object{"name": "b", "size": 2.0},
+			expected: object{"name": "b", "size": 2.0, "color": nil},
+		},
+		{
+			name:     "nested removal",
+			olds:     object{"db": object{"host": "h", "port": 5432.0}},
+			news:     object{"db": object{"host": "h"}},
+			expected: object{"db": object{"port": nil}},
+		},
+		{
+			name:     "nested object replaced by a scalar",
+			olds:     object{"db": object{"host": "h"}},
+			news:     object{"db": "none"},
+			expected: object{"db": "none"},
+		},
+		{
+			name:     "arrays are replaced as a whole",
+			olds:     object{"tags": array{"x", "y"}},
+			news:     object{"tags": array{"x"}},
+			expected: object{"tags": array{"x"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := mergePatch(tt.olds, tt.news); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("mergePatch() = %v, expected %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		olds, news object
+		expected   array
+	}{
+		{
+			name:     "unchanged",
+			olds:     object{"name": "a"},
+			news:     object{"name": "a"},
+			expected: array{},
+		},
+		{
+			name: "added, replaced, and removed properties",
+			olds: object{"name": "a", "color": "red"},
+			news: object{"name": "b", "size": 2.0},
+			expected: array{
+				object{"op": "remove", "path": "/color"},
+				object{"op": "replace", "path": "/name", "value": "b"},
+				object{"op": "add", "path": "/size", "value": 2.0},
+			},
+		},
+		{
+			name: "nested objects",
+			olds: object{"db": object{"host": "h", "port": 5432.0}},
+			news: object{"db": object{"host": "g"}},
+			expected: array{
+				object{"op": "replace", "path": "/db/host", "value": "g"},
+				object{"op": "remove", "path": "/db/port"},
+			},
+		},
+		{
+			name: "array grows at its end",
+			olds: object{"tags": array{"x"}},
+			news: object{"tags": array{"y", "z", "w"}},
+			expected: array{
+				object{"op": "replace", "path": "/tags/0", "value": "y"},
+				object{"op": "add", "path": "/tags/1", "value": "z"},
+				object{"op": "add", "path": "/tags/2", "value": "w"},
+			},
+		},
+		{
+			name: "array shrinks from its end",
+			olds: object{"tags": array{"x", "y", "z"}},
+			news: object{"tags": array{"x"}},
+			expected: array{
+				object{"op": "remove", "path": "/tags/2"},
+				object{"op": "remove", "path": "/tags/1"},
+			},
+		},
+		{
+			name: "objects in arrays",
+			olds: object{"rules": array{object{"port": 80.0}}},
+			news: object{"rules": array{object{"port": 443.0}}},
+			expected: array{
+				object{"op": "replace", "path": "/rules/0/port", "value": 443.0},
+			},
+		},
+		{
+			name: "escaped property names",
+			olds: object{"a/b": "x", "c~d": "y"},
+			news: object{"a/b": "z"},
+			expected: array{
+				object{"op": "replace", "path": "/a~1b", "value": "z"},
+				object{"op": "remove", "path": "/c~0d"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := jsonPatch(tt.olds, tt.news); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("jsonPatch() = %v, expected %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEscapePointer(t *testing.T) {
+	tests := []struct {
+		name, expected string
+	}{
+		{"name", "name"},
+		{"a/b", "a~1b"},
+		{"a~b", "a~0b"},
+		// The tilde is escaped first, so that the escaped slash isn't escaped again.
+		{"~/", "~0~1"},
+		{"~1", "~01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := escapePointer(tt.name); actual != tt.expected {
+				t.Errorf("escapePointer(%q) = %q, expected %q", tt.name, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     ResourceMetadata
+		method   string
+		expected string
+	}{
+		{"PUT replaces", ResourceMetadata{}, "PUT", "replace"},
+		{"PATCH merges", ResourceMetadata{}, "PATCH", "merge-patch"},
+		{"declared strategy", ResourceMetadata{UpdateStrategy: "json-patch"}, "PATCH", "json-patch"},
+		{"declared strategy overrides PUT", ResourceMetadata{UpdateStrategy: "merge-patch"}, "PUT", "merge-patch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := updateStrategy(&tt.meta, tt.method); actual != tt.expected {
+				t.Errorf("updateStrategy() = %q, expected %q", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil/rpcerror"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"google.golang.org/grpc/codes"
+)
+
+// pollingError is returned when the API accepted an operation that then didn't complete successfully. It carries the
+// last known state of the resource, if any.
+type pollingError struct {
+	state map[string]interface{}
+	err   error
+}
+
+func (e *pollingError) Error() string {
+	return e.err.Error()
+}
+
+func (e *pollingError) Unwrap() error {
+	return e.err
+}
+
+// sendOperation sends a request that creates, updates, or deletes a resource. If the operation is declared as
+// long-running and the API accepts it for asynchronous processing, sendOperation waits until the operation completes
+// and responds with the final state of the resource. A new resource is located through the inputs it was created
+// with, which address its parent, and the ID that the API assigned to it.
+func (p *xyzProvider) sendOperation(ctx context.Context, urn resource.URN, action string, req *apiRequest,
+	resourceUrl string, inputs map[string]interface{}) (*apiResponse, error) {
+	res, err := p.send(ctx, urn, req)
+	if err != nil {
+		return nil, err
+	}
+
+	polling, ok := p.metadata.Resources[urn.Type().String()].Polling[action]
+	if !ok || res.statusCode != http.StatusAccepted {
+		return res, nil
+	}
+
+	// A new resource can only be located once the API has assigned an ID to it.
+	if action == "create" {
+		if id, ok := p.resourceId(urn.Type().String(), mergeState(inputs, res.body)); ok {
+			resourceUrl = p.baseUrl() + id
+		}
+	}
+
+	state, header, err := p.poll(ctx, urn, action, &polling, req, resourceUrl, res)
+	if err != nil {
+		if state == nil {
+			state = res.body
+		}
+		return nil, &pollingError{state: state, err: err}
+	}
+	return &apiResponse{statusCode: http.StatusOK, header: header, value: state, body: state}, nil
+}
+
+// poll waits for an accepted operation to complete. The progress is tracked through the status URL in the
+// Operation-Location header if there is one, through the Location header otherwise, or by reading the resource
+// itself as a last resort. The final state comes with the headers of the response that returned it, such as its
+// entity tag.
+func (p *xyzProvider) poll(ctx context.Context, urn resource.URN, action string, polling *PollingMetadata,
+	req *apiRequest, resourceUrl string, accepted *apiResponse) (map[string]interface{}, http.Header, error) {
+	requestUrl := req.url
+	statusUrl := resolveUrl(requestUrl, accepted.header.Get("Operation-Location"))
+	if statusUrl == "" {
+		statusUrl = resolveUrl(requestUrl, accepted.header.Get("Azure-AsyncOperation"))
+	}
+	location := resolveUrl(requestUrl, accepted.header.Get("Location"))
+	deleting := action == "delete"
+	goneCodes := p.goneCodes(urn, true)
+
+	state := accepted.body
+	header := accepted.header
+	for attempt := 0; ; attempt++ {
+		_ = p.host.LogStatus(ctx, diag.Info, urn, fmt.Sprintf("waiting for %s to complete", action))
+		select {
+		case <-ctx.Done():
+			return state, nil, p.requestError(ctx, "GET", requestUrl, ctx.Err())
+		case <-time.After(retryDelay(attempt, header)):
+		}
+
+		switch {
+		case statusUrl != "":
+			res, err := p.followUp(ctx, urn, req, statusUrl)
+			if err != nil {
+				return state, nil, err
+			}
+			header = res.header
+
+			switch status := operationStatus(res.body, "status"); {
+			case isSucceeded(status):
+				return p.finalState(ctx, urn, action, polling, req, resourceUrl, location, state)
+			case isFailed(status):
+				return state, nil, errors.Errorf("%s finished with status %q: %s", action, status,
+					errorDetail(res.body))
+			}
+		case location != "" && polling.FinalStateVia != "original-uri":
+			res, err := p.followUp(ctx, urn, req, location)
+			if deleting && isGone(err, goneCodes) {
+				return nil, nil, nil
+			}
+			if err != nil {
+				return state, nil, err
+			}
+			header = res.header
+
+			if res.statusCode != http.StatusAccepted {
+				if deleting {
+					return nil, nil, nil
+				}
+				return res.body, res.header, nil
+			}
+		case resourceUrl != "":
+			res, err := p.followUp(ctx, urn, req, resourceUrl)
+			if deleting && isGone(err, goneCodes) {
+				return nil, nil, nil
+			}
+			if err != nil {
+				return state, nil, err
+			}
+			header = res.header
+			state = res.body
+
+			if deleting {
+				continue
+			}
+			switch status := operationStatus(state, polling.StatusProperty); {
+			case polling.StatusProperty == "" || isSucceeded(status):
+				return state, res.header, nil
+			case isFailed(status):
+				return state, nil, errors.Errorf("%s finished with status %q: %s", action, status,
+					errorDetail(state))
+			}
+		default:
+			return state, nil, errors.Errorf(
+				"the API accepted the %s request but provided no way to track its progress", action)
+		}
+	}
+}
+
+// finalState reads the state of a resource once the operation that was tracked through a status URL succeeded.
+// Without a URL to read it from, the last known state is returned without headers.
+func (p *xyzProvider) finalState(ctx context.Context, urn resource.URN, action string, polling *PollingMetadata,
+	req *apiRequest, resourceUrl, location string, state map[string]interface{}) (map[string]interface{}, http.Header,
+	error) {
+	var finalUrl string
+	switch {
+	case action == "delete":
+		return nil, nil, nil
+	case polling.FinalStateVia == "location" && location != "":
+		finalUrl = location
+	case resourceUrl != "":
+		finalUrl = resourceUrl
+	case location != "":
+		finalUrl = location
+	default:
+		return state, nil, nil
+	}
+
+	res, err := p.followUp(ctx, urn, req, finalUrl)
+	if err != nil {
+		return state, nil, err
+	}
+	return res.body, res.header, nil
+}
+
+// followUp reads a URL that the API returned for a request, with the credentials of the request.
+func (p *xyzProvider) followUp(ctx context.Context, urn resource.URN, req *apiRequest, rawurl string) (*apiResponse,
+	error) {
+	return p.send(ctx, urn, &apiRequest{method: "GET", url: rawurl, retryable: true, security: req.security})
+}
+
+// resolveUrl resolves a URL from a response header against the URL of the request. It returns an empty string if
+// there is no URL.
+func resolveUrl(requestUrl, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	base, err := url.Parse(requestUrl)
+	if err != nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// operationStatus reads the status of an operation or the provisioning state of a resource from a response body.
+func operationStatus(body map[string]interface{}, property string) string {
+	if property == "" {
+		return ""
+	}
+	if status, ok := body[property].(string); ok {
+		return status
+	}
+	// Some APIs nest the provisioning state in a properties bag.
+	if props, ok := body["properties"].(map[string]interface{}); ok {
+		status, _ := props[property].(string)
+		return status
+	}
+	return ""
+}
+
+func isSucceeded(status string) bool {
+	switch strings.ToLower(status) {
+	case "succeeded", "success", "successful", "completed", "complete", "done", "ready":
+		return true
+	default:
+		return false
+	}
+}
+
+func isFailed(status string) bool {
+	switch strings.ToLower(status) {
+	case "failed", "failure", "error", "canceled", "cancelled", "aborted":
+		return true
+	default:
+		return false
+	}
+}
+
+// errorDetail extracts the error reported by the server from a response body.
+func errorDetail(body map[string]interface{}) string {
+	detail := interface{}(body)
+	if e, ok := body["error"]; ok {
+		detail = e
+	}
+	b, err := json.Marshal(detail)
+	if err != nil {
+		return fmt.Sprint(detail)
+	}
+	return string(b)
+}
+
+// initializationError reports a resource that exists but failed to finish provisioning. The engine records the
+// partial state, so that the resource isn't orphaned and can be fixed by a later update.
+func initializationError(id string, state, inputs resource.PropertyMap, reason error) error {
+	props, err := plugin.MarshalProperties(
+		withInputs(state, inputs),
+		plugin.MarshalOptions{SkipNulls: true, KeepSecrets: true},
+	)
+	if err != nil {
+		return reason
+	}
+	inputsStruct, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{SkipNulls: true, KeepSecrets: true})
+	if err != nil {
+		return reason
+	}
+
+	return rpcerror.WithDetails(
+		rpcerror.New(codes.Unknown, reason.Error()),
+		&rpc.ErrorResourceInitFailed{
+			Id:         id,
+			Properties: props,
+			Inputs:     inputsStruct,
+			Reasons:    []string{reason.Error()},
+		},
+	)
+}
@@ -22,8 +22,10 @@ import (
 	"fmt"
 	pbempty "github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/pkg/v3/codegen"
 	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
@@ -31,14 +33,26 @@ import (
 	"google.golang.org/grpc/status"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
 )
 
 type xyzProvider struct {
-	host     *provider.HostClient
-	name     string
-	version  string
-	pkgSpec  *schema.PackageSpec
-	metadata *APIMetadata
+	host       *provider.HostClient
+	name       string
+	version    string
+	schemaJSON []byte
+	pkgSpec    *schema.PackageSpec
+	metadata   *APIMetadata
+	config     *providerConfig
+	client     *http.Client
+	oauth2     *oauth2Token
+
+	// canceled is done once the engine asks the provider to abort all ongoing operations.
+	canceled context.Context
+	cancel   context.CancelFunc
 }
 
 func makeProvider(host *provider.HostClient, name, version string, schemaBytes []byte,
@@ -48,8 +62,13 @@ func makeProvider(host *provider.HostClient, name, version string, schemaBytes [
 		return nil, errors.Wrap(err, "expand compressed schema")
 	}
 
+	schemaJSON, err := ioutil.ReadAll(uncompressed)
+	if err != nil {
+		return nil, errors.Wrap(err, "expand compressed schema")
+	}
+
 	var pkgSpec schema.PackageSpec
-	if err = json.NewDecoder(uncompressed).Decode(&pkgSpec); err != nil {
+	if err = json.Unmarshal(schemaJSON, &pkgSpec); err != nil {
 		return nil, fmt.Errorf("deserializing schema: %w", err)
 	}
 
@@ -65,40 +84,132 @@ func makeProvider(host *provider.HostClient, name, version string, schemaBytes [
 		return nil, errors.Wrap(err, "closing uncompress stream for metadata")
 	}
 
+	canceled, cancel := context.WithCancel(context.Background())
+
 	// Return the new provider
 	return &xyzProvider{
-		host:     host,
-		name:     name,
-		version:  version,
-		pkgSpec:  &pkgSpec,
-		metadata: &metadata,
+		host:       host,
+		name:       name,
+		version:    version,
+		schemaJSON: schemaJSON,
+		pkgSpec:    &pkgSpec,
+		metadata:   &metadata,
+		config:     &providerConfig{maxRetries: defaultMaxRetries},
+		client:     &http.Client{},
+		oauth2:     &oauth2Token{},
+		canceled:   canceled,
+		cancel:     cancel,
 	}, nil
 }
 
 // CheckConfig validates the configuration for this provider.
 func (p *xyzProvider) CheckConfig(_ context.Context, req *rpc.CheckRequest) (*rpc.CheckResponse, error) {
-	return &rpc.CheckResponse{Inputs: req.GetNews()}, nil
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		KeepUnknowns: true,
+		KeepSecrets:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, failures := parseConfig(p.configValues(news), p.metadata.Security)
+	return &rpc.CheckResponse{Inputs: req.GetNews(), Failures: failures}, nil
 }
 
 // DiffConfig diffs the configuration for this provider.
-func (p *xyzProvider) DiffConfig(_ context.Context, _ *rpc.DiffRequest) (*rpc.DiffResponse, error) {
-	return &rpc.DiffResponse{}, nil
+func (p *xyzProvider) DiffConfig(_ context.Context, req *rpc.DiffRequest) (*rpc.DiffResponse, error) {
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{KeepUnknowns: true, SkipNulls: true})
+	if err != nil {
+		return nil, err
+	}
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{KeepUnknowns: true, SkipNulls: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// The engine records the plugin version in the provider inputs, which isn't a part of the configuration.
+	ignoreVersion := func(key resource.PropertyKey) bool { return key == "version" }
+	diff := olds.Diff(news, ignoreVersion, ignoreChanges(req.GetIgnoreChanges()))
+	return diffResponse(diff, codegen.NewStringSet(configReplaces...)), nil
 }
 
 // Configure configures the resource provider with "globals" that control its behavior.
-func (p *xyzProvider) Configure(_ context.Context, _ *rpc.ConfigureRequest) (*rpc.ConfigureResponse, error) {
+func (p *xyzProvider) Configure(_ context.Context, req *rpc.ConfigureRequest) (*rpc.ConfigureResponse, error) {
+	config, failures := parseConfig(p.configValues(p.configVariables(req.GetVariables())), p.metadata.Security)
+	if len(failures) > 0 {
+		var reasons []string
+		for _, f := range failures {
+			reasons = append(reasons, f.Reason)
+		}
+		return nil, errors.Errorf("invalid provider configuration: %s", strings.Join(reasons, "; "))
+	}
+
+	client, err := config.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	p.config = config
+	p.client = client
+	p.oauth2 = &oauth2Token{}
 	return &rpc.ConfigureResponse{}, nil
 }
 
 // Invoke dynamically executes a built-in function in the provider.
-func (p *xyzProvider) Invoke(_ context.Context, _ *rpc.InvokeRequest) (*rpc.InvokeResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "StreamInvoke is not yet implemented")
+func (p *xyzProvider) Invoke(ctx context.Context, req *rpc.InvokeRequest) (*rpc.InvokeResponse, error) {
+	tok := req.GetTok()
+	fn, ok := p.pkgSpec.Functions[tok]
+	meta, hasMeta := p.metadata.Functions[tok]
+	if !ok || !hasMeta {
+		return nil, fmt.Errorf("unknown function %q", tok)
+	}
+
+	args, err := plugin.UnmarshalProperties(req.GetArgs(), plugin.MarshalOptions{SkipNulls: true})
+	if err != nil {
+		return nil, err
+	}
+	if failures := checkProperties(fn.Inputs.Properties, fn.Inputs.Required, nil, args); len(failures) > 0 {
+		return &rpc.InvokeResponse{Failures: failures}, nil
+	}
+
+	ctx, cancel := p.operationContext(ctx, 0)
+	defer cancel()
+
+	url := fmt.Sprintf("%s%s", p.baseUrl(), functionPath(&meta, args))
+	res, err := p.send(ctx, "", &apiRequest{method: "GET", url: url, retryable: true, security: meta.Security})
+	if err != nil {
+		return nil, err
+	}
+
+	result := res.body
+	if meta.ItemsProperty != "" {
+		result = map[string]interface{}{meta.ItemsProperty: res.value}
+	}
+	// Secret properties of the result stay secret in the program that invoked the function.
+	resultProps := resource.NewPropertyMapFromMap(result)
+	if fn.Outputs != nil {
+		resultProps = p.markSecrets(fn.Outputs.Properties, resultProps)
+	}
+	outputs, err := plugin.MarshalProperties(
+		resultProps,
+		plugin.MarshalOptions{SkipNulls: true, KeepSecrets: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpc.InvokeResponse{Return: outputs}, nil
 }
 
 // StreamInvoke dynamically executes a built-in function in the provider. The result is streamed
 // back as a series of messages.
-func (p *xyzProvider) StreamInvoke(_ *rpc.InvokeRequest, _ rpc.ResourceProvider_StreamInvokeServer) error {
-	return status.Error(codes.Unimplemented, "StreamInvoke is not yet implemented")
+func (p *xyzProvider) StreamInvoke(req *rpc.InvokeRequest, server rpc.ResourceProvider_StreamInvokeServer) error {
+	// API functions return a single result, so the stream consists of one message.
+	res, err := p.Invoke(server.Context(), req)
+	if err != nil {
+		return err
+	}
+	return server.Send(res)
 }
 
 // Check validates that the given property bag is valid for a resource of the given type and returns
@@ -110,42 +221,94 @@ func (p *xyzProvider) StreamInvoke(_ *rpc.InvokeRequest, _ rpc.ResourceProvider_
 func (p *xyzProvider) Check(_ context.Context, req *rpc.CheckRequest) (*rpc.CheckResponse, error) {
 	typ := resource.URN(req.GetUrn()).Type()
 
-	if _, ok := p.pkgSpec.Resources[typ.String()]; !ok {
+	res, ok := p.pkgSpec.Resources[typ.String()]
+	if !ok {
 		return nil, fmt.Errorf("unknown resource type %q", typ)
 	}
-	return &rpc.CheckResponse{Inputs: req.News, Failures: nil}, nil
+
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{
+		KeepUnknowns: true,
+		KeepSecrets:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	constraints := p.metadata.Resources[typ.String()].Constraints
+	failures := checkProperties(res.InputProperties, res.RequiredInputs, constraints, news)
+	return &rpc.CheckResponse{Inputs: req.News, Failures: failures}, nil
 }
 
 // Diff checks what impacts a hypothetical update will have on the resource's properties.
-func (p *xyzProvider) Diff(_ context.Context, _ *rpc.DiffRequest) (*rpc.DiffResponse, error) {
-	return &rpc.DiffResponse{}, nil
+func (p *xyzProvider) Diff(_ context.Context, req *rpc.DiffRequest) (*rpc.DiffResponse, error) {
+	typ := resource.URN(req.GetUrn()).Type()
+	res, ok := p.pkgSpec.Resources[typ.String()]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type %q", typ)
+	}
+
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{KeepUnknowns: true, SkipNulls: true})
+	if err != nil {
+		return nil, err
+	}
+	news, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{KeepUnknowns: true, SkipNulls: true})
+	if err != nil {
+		return nil, err
+	}
+
+	diff := oldInputs(&res, olds).Diff(news, ignoreChanges(req.GetIgnoreChanges()))
+	return diffResponse(diff, p.replaceOnChanges(typ.String(), &res)), nil
 }
 
 // Create allocates a new instance of the provided resource and returns its unique ID afterwards.
-func (p *xyzProvider) Create(_ context.Context, req *rpc.CreateRequest) (*rpc.CreateResponse, error) {
+func (p *xyzProvider) Create(ctx context.Context, req *rpc.CreateRequest) (*rpc.CreateResponse, error) {
+	ctx, cancel := p.operationContext(ctx, req.GetTimeout())
+	defer cancel()
 
-	inputs, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{SkipNulls: true})
+	urn := resource.URN(req.GetUrn())
+	tok := urn.Type().String()
+	inputs, err := p.unmarshalInputs(tok, req.GetProperties())
 	if err != nil {
 		return nil, err
 	}
-	inputsMap := inputs.Mappable()
+	inputsMap := plainMap(inputs)
 
-	typ := resource.URN(req.GetUrn()).Type()
-	path := p.metadata.ResourceUrls[typ.String()]
-	url := fmt.Sprintf("%s%s", p.metadata.BaseUrl, path)
+	apiReq, err := p.operationRequest(tok, "create", "", nil, inputsMap)
+	if err != nil {
+		return nil, err
+	}
 
-	outputsMap, err := sendRequestWithTimeout("POST", url, inputsMap)
+	response, err := p.sendOperation(ctx, urn, "create", apiReq, "", inputsMap)
 	if err != nil {
+		// If the API accepted the resource but it failed to finish provisioning, report it as partially created
+		// so that the engine keeps track of it.
+		var pollErr *pollingError
+		if errors.As(err, &pollErr) {
+			if id, ok := p.resourceId(tok, mergeState(inputsMap, pollErr.state)); ok {
+				return nil, initializationError(id, p.secretOutputs(tok, pollErr.state, inputs), inputs, pollErr)
+			}
+		}
 		return nil, err
 	}
 
-	// Note that we assume this particular structure of all resource IDs. The ID is then used for all
-	// update, read, and delete operations.
-	id := fmt.Sprintf("%s/%s", path, outputsMap["id"])
+	// An API that responds without a body leaves the resource with the state it was created with.
+	outputsMap := response.body
+	if len(outputsMap) == 0 {
+		outputsMap = plainMap(inputs)
+	}
+
+	// The ID is the path of the resource, which is then used for all update, read, and delete operations. The path
+	// of a nested resource takes the ID of its parent from the inputs.
+	id, ok := p.resourceId(tok, mergeState(inputsMap, outputsMap))
+	if !ok {
+		return nil, errors.Errorf("the response to creating %s doesn't identify the resource", urn.Name())
+	}
+	meta := p.metadata.Resources[tok]
+	outputsMap = withETag(&meta, withPathValues(&meta, id, outputsMap, inputsMap), response.header)
 
 	outputs, err := plugin.MarshalProperties(
-		resource.NewPropertyMapFromMap(outputsMap),
-		plugin.MarshalOptions{SkipNulls: true},
+		withInputs(withWriteOnly(&meta, p.secretOutputs(tok, outputsMap, inputs), inputs), inputs),
+		plugin.MarshalOptions{SkipNulls: true, KeepSecrets: true},
 	)
 	if err != nil {
 		return nil, err
@@ -158,17 +321,88 @@ func (p *xyzProvider) Create(_ context.Context, req *rpc.CreateRequest) (*rpc.Cr
 }
 
 // Read the current live state associated with a resource.
-func (p *xyzProvider) Read(_ context.Context, req *rpc.ReadRequest) (*rpc.ReadResponse, error) {
+func (p *xyzProvider) Read(ctx context.Context, req *rpc.ReadRequest) (*rpc.ReadResponse, error) {
+	ctx, cancel := p.operationContext(ctx, 0)
+	defer cancel()
+
+	urn := resource.URN(req.GetUrn())
+	typ := urn.Type()
+	res, ok := p.pkgSpec.Resources[typ.String()]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type %q", typ)
+	}
+
+	// Without a read operation, the state that the resource was created with is the best there is.
+	if !p.metadata.Resources[typ.String()].Readable {
+		if len(req.GetProperties().GetFields()) == 0 {
+			return nil, errors.Errorf("%s can't be imported because the API has no operation to read it", urn.Name())
+		}
+		return &rpc.ReadResponse{Id: req.GetId(), Properties: req.GetProperties(), Inputs: req.GetInputs()}, nil
+	}
+
+	// Imports may reference the resource by its bare backend ID rather than the full path that the provider uses as
+	// the resource ID.
 	id := req.GetId()
-	url := fmt.Sprintf("%s%s", p.metadata.BaseUrl, id)
+	if !strings.HasPrefix(id, "/") {
+		meta := p.metadata.Resources[typ.String()]
+		path, ok := p.resourceId(typ.String(), map[string]interface{}{idProperty(&meta): id})
+		if !ok {
+			return nil, errors.Errorf("can't resolve the ID %q of %s, use the full path of the resource, e.g. %q",
+				id, urn.Name(), meta.ResourcePath)
+		}
+		id = path
+	}
 
-	outputsMap, err := sendRequestWithTimeout("GET", url, nil)
+	olds, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{SkipNulls: true,
+		KeepSecrets: true})
+	if err != nil {
+		return nil, err
+	}
+	oldsMap := plainMap(olds)
+	apiReq, err := p.operationRequest(typ.String(), "read", id, oldsMap, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	response, err := p.send(ctx, urn, apiReq)
+	if isGone(err, p.goneCodes(urn, false)) {
+		// The resource was deleted outside of Pulumi. An empty ID tells the engine to remove it from the state.
+		return &rpc.ReadResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	meta := p.metadata.Resources[typ.String()]
+	outputsMap := withETag(&meta, withPathValues(&meta, id, response.body, oldsMap), response.header)
+
+	oldInputs, err := plugin.UnmarshalProperties(req.GetInputs(), plugin.MarshalOptions{SkipNulls: true,
+		KeepSecrets: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the inputs that the resource was deployed with. When there are none, e.g. during an import, reconstruct
+	// them from the live state so that the next update doesn't show a diff for every property. Write-only properties
+	// keep their prior values, so an import leaves them out of the inputs.
+	inputs := oldInputs
+	if len(inputs) == 0 && olds[inputsKey].IsObject() {
+		inputs = olds[inputsKey].ObjectValue()
+	}
+	inputs = p.markSecrets(res.InputProperties, inputs)
+	newState := withWriteOnly(&meta, p.secretOutputs(typ.String(), outputsMap, inputs), olds, inputs)
+	if len(inputs) == 0 {
+		inputs = projectInputs(&res, newState)
+	}
+
 	outputs, err := plugin.MarshalProperties(
-		resource.NewPropertyMapFromMap(outputsMap),
+		withInputs(newState, inputs),
+		plugin.MarshalOptions{KeepSecrets: true, KeepUnknowns: true, SkipNulls: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+	inputsStruct, err := plugin.MarshalProperties(
+		inputs,
 		plugin.MarshalOptions{KeepSecrets: true, KeepUnknowns: true, SkipNulls: true},
 	)
 	if err != nil {
@@ -178,28 +412,86 @@ func (p *xyzProvider) Read(_ context.Context, req *rpc.ReadRequest) (*rpc.ReadRe
 	return &rpc.ReadResponse{
 		Id:         id,
 		Properties: outputs,
-		Inputs:     req.GetProperties(),
+		Inputs:     inputsStruct,
 	}, nil
 }
 
 // Update updates an existing resource with new values.
-func (p *xyzProvider) Update(_ context.Context, req *rpc.UpdateRequest) (*rpc.UpdateResponse, error) {
-	url := fmt.Sprintf("%s%s", p.metadata.BaseUrl, req.GetId())
+func (p *xyzProvider) Update(ctx context.Context, req *rpc.UpdateRequest) (*rpc.UpdateResponse, error) {
+	ctx, cancel := p.operationContext(ctx, req.GetTimeout())
+	defer cancel()
+
+	// Diff replaces resources that the API can't update, so this is only reached if the diff was overridden.
+	urn := resource.URN(req.GetUrn())
+	tok := urn.Type().String()
+	meta := p.metadata.Resources[tok]
+	if !meta.Updatable {
+		return nil, errors.Errorf("%s can't be updated in place because the API has no operation to update it",
+			urn.Name())
+	}
 
-	inputs, err := plugin.UnmarshalProperties(req.GetNews(), plugin.MarshalOptions{SkipNulls: true})
+	olds, err := plugin.UnmarshalProperties(req.GetOlds(), plugin.MarshalOptions{SkipNulls: true, KeepSecrets: true})
 	if err != nil {
 		return nil, err
 	}
-	inputsMap := inputs.Mappable()
+	oldsMap := plainMap(olds)
+	inputs, err := p.unmarshalInputs(tok, req.GetNews())
+	if err != nil {
+		return nil, err
+	}
+	inputsMap := plainMap(inputs)
 
-	outputsMap, err := sendRequestWithTimeout("PATCH", url, inputsMap)
+	apiReq, err := p.operationRequest(tok, "update", req.GetId(), oldsMap, inputsMap)
 	if err != nil {
 		return nil, err
 	}
 
+	setIfMatch(&meta, apiReq, oldsMap)
+
+	// Patches only carry the changes to the inputs.
+	if newBody, ok := apiReq.body.(map[string]interface{}); ok {
+		var op *OperationMetadata
+		if o, ok := meta.Operations["update"]; ok {
+			op = &o
+		}
+		res := p.pkgSpec.Resources[tok]
+		oldBody := requestBody(op, plainMap(oldInputs(&res, olds)))
+		switch updateStrategy(&meta, apiReq.method) {
+		case "merge-patch":
+			apiReq.body = mergePatch(oldBody, newBody)
+		case "json-patch":
+			apiReq.body = jsonPatch(oldBody, newBody)
+			if apiReq.contentType == "" {
+				apiReq.contentType = "application/json-patch+json"
+			}
+			// Applying a JSON patch twice can have a different effect than applying it once, e.g. when it removes
+			// an array item by its index. A repeated attempt is only safe if the API rejects it once the first
+			// attempt changed the entity tag of the resource.
+			apiReq.retryable = apiReq.header.Get("If-Match") != ""
+		}
+	}
+
+	response, err := p.sendOperation(ctx, urn, "update", apiReq, p.baseUrl()+req.GetId(), nil)
+	if err != nil {
+		var pollErr *pollingError
+		if errors.As(err, &pollErr) && pollErr.state != nil {
+			return nil, initializationError(req.GetId(), p.secretOutputs(tok, pollErr.state, inputs), inputs,
+				pollErr)
+		}
+		return nil, preconditionError(urn, err)
+	}
+	// An API that responds without a body leaves the resource with its prior state, changed by the new inputs.
+	outputsMap := response.body
+	if len(outputsMap) == 0 {
+		outputsMap = mergeState(oldsMap, inputsMap)
+	}
+	outputsMap = withPathValues(&meta, req.GetId(), outputsMap, oldsMap)
+	outputsMap = withETag(&meta, outputsMap, response.header)
+
+	// Write-only properties take their values from the new inputs only, so that removing one drops it from the state.
 	outputs, err := plugin.MarshalProperties(
-		resource.NewPropertyMapFromMap(outputsMap),
-		plugin.MarshalOptions{SkipNulls: true},
+		withInputs(withWriteOnly(&meta, p.secretOutputs(tok, outputsMap, inputs), inputs), inputs),
+		plugin.MarshalOptions{SkipNulls: true, KeepSecrets: true},
 	)
 	if err != nil {
 		return nil, err
@@ -212,13 +504,37 @@ func (p *xyzProvider) Update(_ context.Context, req *rpc.UpdateRequest) (*rpc.Up
 
 // Delete tears down an existing resource with the given ID.  If it fails, the resource is assumed
 // to still exist.
-func (p *xyzProvider) Delete(_ context.Context, req *rpc.DeleteRequest) (*pbempty.Empty, error) {
-	url := fmt.Sprintf("%s%s", p.metadata.BaseUrl, req.GetId())
+func (p *xyzProvider) Delete(ctx context.Context, req *rpc.DeleteRequest) (*pbempty.Empty, error) {
+	ctx, cancel := p.operationContext(ctx, req.GetTimeout())
+	defer cancel()
+
+	urn := resource.URN(req.GetUrn())
+	if !p.metadata.Resources[urn.Type().String()].Deletable {
+		msg := "the API has no operation to delete the resource, so it is only removed from the Pulumi state"
+		if err := p.host.Log(ctx, diag.Warning, urn, msg); err != nil {
+			return nil, err
+		}
+		return &pbempty.Empty{}, nil
+	}
 
-	_, err := sendRequestWithTimeout("DELETE", url, nil)
+	state, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{SkipNulls: true,
+		KeepSecrets: true})
 	if err != nil {
 		return nil, err
 	}
+	stateMap := plainMap(state)
+	apiReq, err := p.operationRequest(urn.Type().String(), "delete", req.GetId(), stateMap, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := p.metadata.Resources[urn.Type().String()]
+	setIfMatch(&meta, apiReq, stateMap)
+
+	_, err = p.sendOperation(ctx, urn, "delete", apiReq, p.baseUrl()+req.GetId(), nil)
+	if err != nil && !isGone(err, p.goneCodes(urn, true)) {
+		return nil, preconditionError(urn, err)
+	}
 
 	return &pbempty.Empty{}, nil
 }
@@ -236,8 +552,11 @@ func (p *xyzProvider) GetPluginInfo(context.Context, *pbempty.Empty) (*rpc.Plugi
 }
 
 // GetSchema returns the JSON-serialized schema for the provider.
-func (p *xyzProvider) GetSchema(_ context.Context, _ *rpc.GetSchemaRequest) (*rpc.GetSchemaResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "GetSchema is not yet implemented")
+func (p *xyzProvider) GetSchema(_ context.Context, req *rpc.GetSchemaRequest) (*rpc.GetSchemaResponse, error) {
+	if v := req.GetVersion(); v != 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported schema version %d", v)
+	}
+	return &rpc.GetSchemaResponse{Schema: string(p.schemaJSON)}, nil
 }
 
 // Cancel signals the provider to gracefully shut down and abort any ongoing resource operations.
@@ -246,12 +565,69 @@ func (p *xyzProvider) GetSchema(_ context.Context, _ *rpc.GetSchemaRequest) (*rp
 // to the host to decide how long to wait after Cancel is called before (e.g.)
 // hard-closing any gRPC connection.
 func (p *xyzProvider) Cancel(context.Context, *pbempty.Empty) (*pbempty.Empty, error) {
+	p.cancel()
 	return &pbempty.Empty{}, nil
 }
 
-func sendRequestWithTimeout(method, rawurl string, body map[string]interface{}) (map[string]interface{}, error) {
+// baseUrl returns the URL that all API paths are relative to.
+func (p *xyzProvider) baseUrl() string {
+	if p.config.baseUrl != "" {
+		return p.config.baseUrl
+	}
+	return p.metadata.BaseUrl
+}
+
+// apiRequest is a request to the API.
+type apiRequest struct {
+	method string
+	url    string
+	header http.Header
+	// body is encoded as JSON: an object with properties, or an array with the operations of a JSON patch.
+	body interface{}
+	// contentType is the media type of the body. Defaults to "application/json".
+	contentType string
+	// retryable is true if the request can be safely repeated after a transient failure.
+	retryable bool
+	// security lists the alternative security requirements of the request.
+	security [][]string
+}
+
+// apiResponse is a successful response of the API.
+type apiResponse struct {
+	statusCode int
+	header     http.Header
+	// value is the decoded JSON response, and body is the same value if it is an object.
+	value interface{}
+	body  map[string]interface{}
+}
+
+// send sends a request to the API, retrying it if it is safe to do so. The result is an *httpError if the API
+// responds with an unsuccessful status code.
+func (p *xyzProvider) send(ctx context.Context, urn resource.URN, r *apiRequest) (*apiResponse, error) {
+	method, rawurl, body := r.method, r.url, r.body
 	reqHeaders := make(http.Header)
+	for k, v := range p.config.headers {
+		reqHeaders.Set(k, v)
+	}
+	for k, v := range r.header {
+		reqHeaders[k] = v
+	}
 	reqHeaders.Set("Content-Type", "application/json")
+	if r.contentType != "" {
+		reqHeaders.Set("Content-Type", r.contentType)
+	}
+
+	// Only requests that can be safely repeated are retried. A POST becomes safe to repeat when the API can
+	// recognize repeated attempts by their idempotency key.
+	retryable := r.retryable
+	if method == "POST" && p.config.idempotencyKeyHeader != "" {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return nil, errors.Wrap(err, "generating idempotency key")
+		}
+		reqHeaders.Set(p.config.idempotencyKeyHeader, key)
+		retryable = true
+	}
 
 	var res *http.Response
 	var buf bytes.Buffer
@@ -262,34 +638,375 @@ func sendRequestWithTimeout(method, rawurl string, body map[string]interface{})
 		}
 	}
 
-	req, err := http.NewRequest(method, rawurl, &buf)
-	if err != nil {
-		return nil, err
+	reauthenticated := false
+	for retry := 0; ; retry++ {
+		req, err := http.NewRequestWithContext(ctx, method, rawurl, bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header = reqHeaders.Clone()
+		if err = p.authenticate(req, r.security); err != nil {
+			return nil, err
+		}
+
+		res, err = p.client.Do(req)
+
+		// An OAuth2 token may be revoked before it expires. Get a fresh token and try once more.
+		if err == nil && res.StatusCode == http.StatusUnauthorized && !reauthenticated && p.oauth2.invalidate() {
+			res.Body.Close()
+			reauthenticated = true
+			continue
+		}
+
+		if !retryable || retry >= p.config.maxRetries || !shouldRetry(res, err) {
+			if err != nil {
+				return nil, p.requestError(ctx, method, rawurl, err)
+			}
+			break
+		}
+
+		var header http.Header
+		reason := fmt.Sprint(err)
+		if res != nil {
+			header = res.Header
+			reason = res.Status
+			res.Body.Close()
+		}
+		delay := retryDelay(retry, header)
+		_ = p.host.Log(ctx, diag.Info, urn, fmt.Sprintf("%s %s failed with %s, retrying in %v (retry %d of %d)",
+			method, rawurl, reason, delay.Round(time.Millisecond), retry+1, p.config.maxRetries))
+
+		select {
+		case <-ctx.Done():
+			return nil, p.requestError(ctx, method, rawurl, ctx.Err())
+		case <-time.After(delay):
+		}
 	}
-	req.Header = reqHeaders
 
-	client := http.Client{}
-	res, err = client.Do(req)
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		return nil, p.requestError(ctx, method, rawurl, err)
 	}
 
 	if res.StatusCode >= 300 {
-		defer res.Body.Close()
-		body, _ := ioutil.ReadAll(res.Body)
-		return nil, errors.Errorf("HTTP request failed with %v: %s", res.StatusCode, body)
+		return nil, &httpError{statusCode: res.StatusCode, body: resBody}
 	}
 
-	if res.StatusCode == 204 {
-		return nil, nil
+	result := &apiResponse{statusCode: res.StatusCode, header: res.Header}
+	if res.StatusCode == 204 || len(bytes.TrimSpace(resBody)) == 0 {
+		return result, nil
 	}
 
-	result := make(map[string]interface{})
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		defer res.Body.Close()
-		body, _ := ioutil.ReadAll(res.Body)
-		return nil, errors.Wrapf(err, "decoding JSON %s", body)
+	if err := json.Unmarshal(resBody, &result.value); err != nil {
+		return nil, errors.Wrapf(err, "decoding JSON %s", resBody)
 	}
+	result.body, _ = result.value.(map[string]interface{})
 
 	return result, nil
 }
+
+// defaultOperationTimeout bounds a resource operation when the program doesn't set a custom timeout for it.
+const defaultOperationTimeout = 20 * time.Minute
+
+// operationContext derives the context of a resource operation from the context of the gRPC call. The operation is
+// bounded by the custom timeout (in seconds) that the engine passes for the operation, or by the default timeout,
+// and is aborted when the provider is canceled.
+func (p *xyzProvider) operationContext(ctx context.Context, timeout float64) (context.Context, context.CancelFunc) {
+	d := defaultOperationTimeout
+	if timeout > 0 {
+		d = time.Duration(timeout * float64(time.Second))
+	}
+	ctx, cancel := context.WithTimeout(ctx, d)
+
+	go func() {
+		select {
+		case <-p.canceled.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// requestError explains a failed request, calling out operations that ran out of time or were canceled.
+func (p *xyzProvider) requestError(ctx context.Context, method, rawurl string, err error) error {
+	switch {
+	case p.canceled.Err() != nil:
+		return errors.Errorf("%s %s: operation canceled", method, rawurl)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return errors.Errorf("%s %s: operation timed out; consider increasing the resource's customTimeouts",
+			method, rawurl)
+	default:
+		return err
+	}
+}
+
+// httpError is returned for API responses with an unsuccessful status code.
+type httpError struct {
+	statusCode int
+	body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("HTTP request failed with %v: %s", e.statusCode, e.body)
+}
+
+// isGone returns true if the request failed with one of the given status codes, which the API uses to indicate
+// that a resource doesn't exist.
+func isGone(err error, codes []int) bool {
+	var httpErr *httpError
+	return errors.As(err, &httpErr) && hasStatusCode(codes, httpErr.statusCode)
+}
+
+// hasStatusCode returns true if the status code is one of the given codes.
+func hasStatusCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// goneCodes returns the status codes that indicate that the resource doesn't exist when reading or deleting it.
+// Resources without metadata fall back to the standard Not Found and Gone codes.
+func (p *xyzProvider) goneCodes(urn resource.URN, deleting bool) []int {
+	meta, ok := p.metadata.Resources[urn.Type().String()]
+	switch {
+	case !ok:
+		return []int{http.StatusNotFound, http.StatusGone}
+	case deleting:
+		return meta.DeleteGoneCodes
+	default:
+		return meta.ReadGoneCodes
+	}
+}
+
+// functionPath builds the request path of a function invocation by substituting the arguments into the path template
+// and the query string.
+func functionPath(meta *FunctionMetadata, args resource.PropertyMap) string {
+	path, query := meta.Path, url.Values{}
+	for _, param := range meta.Parameters {
+		v, ok := args[resource.PropertyKey(param.Property)]
+		switch {
+		case !ok:
+		case param.In == "path":
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", url.PathEscape(fmt.Sprint(v.Mappable())))
+		case param.In == "query":
+			addQueryValue(query, param.Name, v.Mappable())
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path
+}
+
+// addQueryValue adds the value of a query parameter to a query string. Arrays add a value per element.
+func addQueryValue(query url.Values, name string, v interface{}) {
+	if items, ok := v.([]interface{}); ok {
+		for _, item := range items {
+			query.Add(name, fmt.Sprint(item))
+		}
+		return
+	}
+	query.Set(name, fmt.Sprint(v))
+}
+
+// legacyMethods are the methods of the resource operations for metadata that doesn't describe the operations.
+var legacyMethods = map[string]string{"create": "POST", "read": "GET", "update": "PATCH", "delete": "DELETE"}
+
+// operationRequest builds the request of an operation of a resource. The parameters take their values from the inputs
+// recorded in the state, the state of the resource, the inputs, and the resource ID, in increasing order of
+// precedence, and the request fails if a required parameter has no value. The body carries the inputs that aren't
+// sent as path, query, or header parameters. Resources whose metadata doesn't describe their operations are
+// addressed by their ID.
+func (p *xyzProvider) operationRequest(tok, action, id string, state, inputs map[string]interface{}) (*apiRequest,
+	error) {
+	meta := p.metadata.Resources[tok]
+	op, ok := meta.Operations[action]
+	if !ok {
+		method := legacyMethods[action]
+		r := &apiRequest{method: method, url: p.baseUrl() + id, header: http.Header{}, retryable: isIdempotent(method)}
+		if action == "create" {
+			r.url = p.baseUrl() + p.metadata.ResourceUrls[tok]
+		}
+		if action == "create" || action == "update" {
+			r.body = inputs
+		}
+		return r, nil
+	}
+
+	// Input-only properties, which the API doesn't return, are only recorded in the inputs of the state.
+	recorded, _ := state[inputsKey].(map[string]interface{})
+	values := mergeState(recorded, state, inputs, resourcePathValues(&meta, id))
+
+	r := &apiRequest{
+		method:      op.Method,
+		header:      http.Header{},
+		contentType: op.RequestContentType,
+		retryable:   isIdempotent(op.Method),
+		security:    op.Security,
+	}
+	if op.ResponseContentType != "" {
+		r.header.Set("Accept", op.ResponseContentType)
+	}
+	path, query := op.Path, url.Values{}
+	for _, param := range op.Parameters {
+		v := values[param.Property]
+		if v == nil && param.In != "body" && (param.Required || param.In == "path") {
+			return nil, errors.Errorf("the %s operation of %s requires a value for '%s'", action, tok,
+				param.Property)
+		}
+		switch {
+		case param.In == "path":
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", url.PathEscape(fmt.Sprint(v)))
+		case param.In == "query" && v != nil:
+			addQueryValue(query, param.Name, v)
+		case param.In == "header" && v != nil:
+			r.header.Set(param.Name, fmt.Sprint(v))
+		}
+	}
+
+	r.url = p.baseUrl() + path
+	if len(query) > 0 {
+		r.url += "?" + query.Encode()
+	}
+	if body := requestBody(&op, inputs); body != nil {
+		r.body = body
+	}
+	return r, nil
+}
+
+// requestBody returns the body of the request of an operation, which carries the inputs that aren't sent as path,
+// query, or header parameters. The result is nil if the request has no body. Without operation metadata, the body
+// carries all inputs.
+func requestBody(op *OperationMetadata, inputs map[string]interface{}) map[string]interface{} {
+	if op == nil {
+		return inputs
+	}
+
+	hasBody, sent := false, map[string]bool{}
+	for _, param := range op.Parameters {
+		if param.In == "body" {
+			hasBody = true
+		} else {
+			sent[param.Property] = true
+		}
+	}
+	if !hasBody {
+		return nil
+	}
+
+	body := map[string]interface{}{}
+	for k, v := range inputs {
+		if !sent[k] {
+			body[k] = v
+		}
+	}
+	return body
+}
+
+// resourcePathValues recovers the values of the properties that the parameters of the resource path map to from the
+// resource ID.
+func resourcePathValues(meta *ResourceMetadata, id string) map[string]interface{} {
+	result := map[string]interface{}{}
+	if meta.ResourcePath == "" {
+		return result
+	}
+
+	// Match the ID against the path template, in which every parameter matches a path segment.
+	literals := pathParameter.Split(meta.ResourcePath, -1)
+	for i := range literals {
+		literals[i] = regexp.QuoteMeta(literals[i])
+	}
+	match := regexp.MustCompile("^" + strings.Join(literals, "([^/]+)") + "$").FindStringSubmatch(id)
+	if match == nil {
+		return result
+	}
+
+	for i, param := range pathParameter.FindAllStringSubmatch(meta.ResourcePath, -1) {
+		prop, ok := meta.PathParameters[param[1]]
+		if !ok {
+			continue
+		}
+		if v, err := url.PathUnescape(match[i+1]); err == nil {
+			result[prop] = v
+		}
+	}
+	return result
+}
+
+// withPathValues adds the properties that the resource ID carries, e.g. the ID of the parent of a nested resource, to
+// the state of a resource when the API doesn't return them. Values from the fallback state are preferred over the
+// ID, since they keep their original types.
+func withPathValues(meta *ResourceMetadata, id string, state, fallback map[string]interface{}) map[string]interface{} {
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	for prop, v := range resourcePathValues(meta, id) {
+		if state[prop] != nil {
+			continue
+		}
+		if fallback[prop] != nil {
+			v = fallback[prop]
+		}
+		state[prop] = v
+	}
+	return state
+}
+
+// mergeState merges property maps, with the values of later maps taking precedence.
+func mergeState(maps ...map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, m := range maps {
+		for k, v := range m {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// pathParameter matches a `{parameter}` template in a path.
+var pathParameter = regexp.MustCompile(`{([^}/]+)}`)
+
+// resourceId returns the ID of a resource, which is the path that addresses it in the API. The path is the resource
+// path template with its parameters substituted by the values of the resource properties that they map to.
+// Resources without a resource path are addressed by the value of their ID property under the creation URL. The
+// result is false if the state lacks a value for the path.
+func (p *xyzProvider) resourceId(tok string, state map[string]interface{}) (string, bool) {
+	meta := p.metadata.Resources[tok]
+	if meta.ResourcePath == "" {
+		id := state[idProperty(&meta)]
+		if id == nil {
+			return "", false
+		}
+		// The creation URL of a nested resource has the path parameters of its parent.
+		collection := p.metadata.ResourceUrls[tok]
+		for _, param := range meta.Operations["create"].Parameters {
+			if v := state[param.Property]; param.In == "path" && v != nil {
+				collection = strings.ReplaceAll(collection, "{"+param.Name+"}", url.PathEscape(fmt.Sprint(v)))
+			}
+		}
+		return fmt.Sprintf("%s/%s", collection, url.PathEscape(fmt.Sprint(id))), true
+	}
+
+	path := meta.ResourcePath
+	for param, prop := range meta.PathParameters {
+		v := state[prop]
+		if v == nil {
+			return "", false
+		}
+		path = strings.ReplaceAll(path, "{"+param+"}", url.PathEscape(fmt.Sprint(v)))
+	}
+	return path, true
+}
+
+// idProperty returns the name of the property that identifies a resource in the API.
+func idProperty(meta *ResourceMetadata) string {
+	if meta.IdProperty == "" {
+		return "id"
+	}
+	return meta.IdProperty
+}
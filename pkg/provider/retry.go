@@ -0,0 +1,113 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"math"
+	mathrand "math/rand"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultMaxRetries is the number of times a failed request is retried unless configured otherwise.
+	defaultMaxRetries = 3
+	// retryBaseDelay is the delay before the first retry. Every next retry waits twice as long.
+	retryBaseDelay = time.Second
+	// retryMaxDelay caps both the exponential backoff and the delay requested by a Retry-After header.
+	retryMaxDelay = time.Minute
+)
+
+// isIdempotent returns true if a request with the given method can be safely repeated.
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "PATCH", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry returns true if the outcome of a request is a transient failure.
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+			errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay returns how long to wait before the given retry (counting from zero). The server's Retry-After header
+// takes precedence; otherwise the delay grows exponentially with a random jitter so that concurrent clients don't
+// retry in lockstep.
+func retryDelay(retry int, header http.Header) time.Duration {
+	if header != nil {
+		if d, ok := parseRetryAfter(header.Get("Retry-After")); ok {
+			if d > retryMaxDelay {
+				return retryMaxDelay
+			}
+			return d
+		}
+	}
+
+	backoff := float64(retryBaseDelay) * math.Pow(2, float64(retry))
+	if backoff > float64(retryMaxDelay) {
+		backoff = float64(retryMaxDelay)
+	}
+	// Equal jitter: wait at least half of the backoff and a random part of the other half.
+	half := time.Duration(backoff / 2)
+	return half + time.Duration(mathrand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// newIdempotencyKey generates a random key that lets the server recognize repeated attempts of the same request.
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
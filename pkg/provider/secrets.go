@@ -0,0 +1,105 @@
+// Copyright 2016-2021, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"strings"
+
+	pbstruct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+)
+
+// plainMap converts properties to the plain values that are sent to the API, revealing the values of secrets.
+func plainMap(props resource.PropertyMap) map[string]interface{} {
+	var reveal func(v resource.PropertyValue) (interface{}, bool)
+	reveal = func(v resource.PropertyValue) (interface{}, bool) {
+		if v.IsSecret() {
+			return v.SecretValue().Element.MapRepl(nil, reveal), true
+		}
+		return nil, false
+	}
+	return props.MapRepl(nil, reveal)
+}
+
+// unmarshalInputs unmarshals the inputs of a resource, keeping their secrets. The values of the input properties
+// that the schema declares as secret become secrets as well.
+func (p *xyzProvider) unmarshalInputs(tok string, props *pbstruct.Struct) (resource.PropertyMap, error) {
+	inputs, err := plugin.UnmarshalProperties(props, plugin.MarshalOptions{SkipNulls: true, KeepSecrets: true})
+	if err != nil {
+		return nil, err
+	}
+	if res, ok := p.pkgSpec.Resources[tok]; ok {
+		inputs = p.markSecrets(res.InputProperties, inputs)
+	}
+	return inputs, nil
+}
+
+// secretOutputs converts the state of a resource returned by the API to properties. The values of the properties
+// that the schema declares as secret are wrapped as secrets, and so are the outputs whose inputs are secret.
+func (p *xyzProvider) secretOutputs(tok string, state map[string]interface{},
+	inputs resource.PropertyMap) resource.PropertyMap {
+	outputs := resource.NewPropertyMapFromMap(state)
+	if res, ok := p.pkgSpec.Resources[tok]; ok {
+		outputs = p.markSecrets(res.Properties, outputs)
+	}
+	for k, v := range inputs {
+		if out, ok := outputs[k]; ok && v.ContainsSecrets() && !out.IsSecret() {
+			outputs[k] = resource.MakeSecret(out)
+		}
+	}
+	return outputs
+}
+
+// markSecrets wraps the values of the properties that the schema declares as secret, including the properties of
+// nested objects, as secrets.
+func (p *xyzProvider) markSecrets(props map[string]schema.PropertySpec,
+	values resource.PropertyMap) resource.PropertyMap {
+	result := resource.PropertyMap{}
+	for k, v := range values {
+		if prop, ok := props[string(k)]; ok {
+			v = p.markSecretValue(&prop.TypeSpec, v)
+			if prop.Secret && !v.IsSecret() {
+				v = resource.MakeSecret(v)
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// markSecretValue wraps the values of secret properties nested in a value of the given type as secrets.
+func (p *xyzProvider) markSecretValue(typ *schema.TypeSpec, v resource.PropertyValue) resource.PropertyValue {
+	switch {
+	case v.IsArray() && typ.Items != nil:
+		items := make([]resource.PropertyValue, len(v.ArrayValue()))
+		for i, item := range v.ArrayValue() {
+			items[i] = p.markSecretValue(typ.Items, item)
+		}
+		return resource.NewArrayProperty(items)
+	case v.IsObject() && typ.AdditionalProperties != nil:
+		values := resource.PropertyMap{}
+		for k, item := range v.ObjectValue() {
+			values[k] = p.markSecretValue(typ.AdditionalProperties, item)
+		}
+		return resource.NewObjectProperty(values)
+	case v.IsObject() && strings.HasPrefix(typ.Ref, "#/types/"):
+		if t, ok := p.pkgSpec.Types[strings.TrimPrefix(typ.Ref, "#/types/")]; ok {
+			return resource.NewObjectProperty(p.markSecrets(t.Properties, v.ObjectValue()))
+		}
+	}
+	return v
+}
@@ -0,0 +1,57 @@
+// *** WARNING: this file was generated by the Pulumi SDK Generator. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+using System.Collections.Immutable;
+
+namespace Pulumi.Xyz
+{
+    public static class Config
+    {
+        private static readonly Pulumi.Config __config = new Pulumi.Config("xyz");
+        /// <summary>
+        /// The base URL of the API. Defaults to `https://functodobackend.azurewebsites.net/api`.
+        /// </summary>
+        public static string? BaseUrl { get; set; } = __config.Get("baseUrl") ?? Utilities.GetEnv("XYZ_BASE_URL");
+
+        /// <summary>
+        /// A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.
+        /// </summary>
+        public static string? CaCertificate { get; set; } = __config.Get("caCertificate") ?? Utilities.GetEnv("XYZ_CA_CERTIFICATE");
+
+        /// <summary>
+        /// A PEM-encoded client certificate for mutual TLS authentication.
+        /// </summary>
+        public static string? ClientCertificate { get; set; } = __config.Get("clientCertificate") ?? Utilities.GetEnv("XYZ_CLIENT_CERTIFICATE");
+
+        /// <summary>
+        /// The PEM-encoded private key of the client certificate.
+        /// </summary>
+        public static string? ClientKey { get; set; } = __config.Get("clientKey") ?? Utilities.GetEnv("XYZ_CLIENT_KEY");
+
+        /// <summary>
+        /// Extra HTTP headers to send with every API request. Can also be set as a JSON object in the `XYZ_HEADERS` environment variable.
+        /// </summary>
+        public static ImmutableDictionary<string, string>? Headers { get; set; } = __config.GetObject<ImmutableDictionary<string, string>>("headers");
+
+        /// <summary>
+        /// The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. When set, POST requests are retried as well, since the API can detect repeated attempts.
+        /// </summary>
+        public static string? IdempotencyKeyHeader { get; set; } = __config.Get("idempotencyKeyHeader") ?? Utilities.GetEnv("XYZ_IDEMPOTENCY_KEY_HEADER");
+
+        /// <summary>
+        /// Disable verification of the API server's TLS certificate.
+        /// </summary>
+        public static bool? InsecureSkipVerify { get; set; } = __config.GetBoolean("insecureSkipVerify") ?? Utilities.GetEnvBoolean("XYZ_INSECURE_SKIP_VERIFY");
+
+        /// <summary>
+        /// The maximum number of times a request that failed with a transient error is retried. Defaults to 3.
+        /// </summary>
+        public static int? MaxRetries { get; set; } = __config.GetInt32("maxRetries") ?? Utilities.GetEnvInt32("XYZ_MAX_RETRIES") ?? 3;
+
+        /// <summary>
+        /// A bearer token to send in the Authorization header of every API request.
+        /// </summary>
+        public static string? Token { get; set; } = __config.Get("token") ?? Utilities.GetEnv("XYZ_TOKEN");
+
+    }
+}
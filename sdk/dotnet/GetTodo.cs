@@ -0,0 +1,61 @@
+// *** WARNING: this file was generated by the Pulumi SDK Generator. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+using System;
+using System.Collections.Generic;
+using System.Collections.Immutable;
+using System.Threading.Tasks;
+using Pulumi.Serialization;
+
+namespace Pulumi.Xyz
+{
+    public static class GetTodo
+    {
+        /// <summary>
+        /// Details of one Todo
+        /// </summary>
+        public static Task<GetTodoResult> InvokeAsync(GetTodoArgs args, InvokeOptions? options = null)
+            => Pulumi.Deployment.Instance.InvokeAsync<GetTodoResult>("xyz:index:getTodo", args ?? new GetTodoArgs(), options.WithVersion());
+    }
+
+
+    public sealed class GetTodoArgs : Pulumi.InvokeArgs
+    {
+        [Input("todoId", required: true)]
+        public string TodoId { get; set; } = null!;
+
+        public GetTodoArgs()
+        {
+        }
+    }
+
+
+    [OutputType]
+    public sealed class GetTodoResult
+    {
+        public readonly bool Completed;
+        public readonly string Id;
+        public readonly int Order;
+        public readonly string Title;
+        public readonly string Url;
+
+        [OutputConstructor]
+        private GetTodoResult(
+            bool completed,
+
+            string id,
+
+            int order,
+
+            string title,
+
+            string url)
+        {
+            Completed = completed;
+            Id = id;
+            Order = order;
+            Title = title;
+            Url = url;
+        }
+    }
+}
@@ -39,8 +39,76 @@ namespace Pulumi.Xyz
 
     public sealed class ProviderArgs : Pulumi.ResourceArgs
     {
+        /// <summary>
+        /// The base URL of the API. Defaults to `https://functodobackend.azurewebsites.net/api`.
+        /// </summary>
+        [Input("baseUrl")]
+        public Input<string>? BaseUrl { get; set; }
+
+        /// <summary>
+        /// A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.
+        /// </summary>
+        [Input("caCertificate")]
+        public Input<string>? CaCertificate { get; set; }
+
+        /// <summary>
+        /// A PEM-encoded client certificate for mutual TLS authentication.
+        /// </summary>
+        [Input("clientCertificate")]
+        public Input<string>? ClientCertificate { get; set; }
+
+        /// <summary>
+        /// The PEM-encoded private key of the client certificate.
+        /// </summary>
+        [Input("clientKey")]
+        public Input<string>? ClientKey { get; set; }
+
+        [Input("headers", json: true)]
+        private InputMap<string>? _headers;
+
+        /// <summary>
+        /// Extra HTTP headers to send with every API request. Can also be set as a JSON object in the `XYZ_HEADERS` environment variable.
+        /// </summary>
+        public InputMap<string> Headers
+        {
+            get => _headers ?? (_headers = new InputMap<string>());
+            set => _headers = value;
+        }
+
+        /// <summary>
+        /// The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. When set, POST requests are retried as well, since the API can detect repeated attempts.
+        /// </summary>
+        [Input("idempotencyKeyHeader")]
+        public Input<string>? IdempotencyKeyHeader { get; set; }
+
+        /// <summary>
+        /// Disable verification of the API server's TLS certificate.
+        /// </summary>
+        [Input("insecureSkipVerify", json: true)]
+        public Input<bool>? InsecureSkipVerify { get; set; }
+
+        /// <summary>
+        /// The maximum number of times a request that failed with a transient error is retried. Defaults to 3.
+        /// </summary>
+        [Input("maxRetries", json: true)]
+        public Input<int>? MaxRetries { get; set; }
+
+        /// <summary>
+        /// A bearer token to send in the Authorization header of every API request.
+        /// </summary>
+        [Input("token")]
+        public Input<string>? Token { get; set; }
+
         public ProviderArgs()
         {
+            BaseUrl = Utilities.GetEnv("XYZ_BASE_URL");
+            CaCertificate = Utilities.GetEnv("XYZ_CA_CERTIFICATE");
+            ClientCertificate = Utilities.GetEnv("XYZ_CLIENT_CERTIFICATE");
+            ClientKey = Utilities.GetEnv("XYZ_CLIENT_KEY");
+            IdempotencyKeyHeader = Utilities.GetEnv("XYZ_IDEMPOTENCY_KEY_HEADER");
+            InsecureSkipVerify = Utilities.GetEnvBoolean("XYZ_INSECURE_SKIP_VERIFY");
+            MaxRetries = Utilities.GetEnvInt32("XYZ_MAX_RETRIES") ?? 3;
+            Token = Utilities.GetEnv("XYZ_TOKEN");
         }
     }
 }
@@ -0,0 +1,86 @@
+// *** WARNING: this file was generated by the Pulumi SDK Generator. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package config
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+// The base URL of the API. Defaults to `https://functodobackend.azurewebsites.net/api`.
+func GetBaseUrl(ctx *pulumi.Context) string {
+	v, err := config.Try(ctx, "xyz:baseUrl")
+	if err == nil {
+		return v
+	}
+	return getEnvOrDefault("", nil, "XYZ_BASE_URL").(string)
+}
+
+// A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.
+func GetCaCertificate(ctx *pulumi.Context) string {
+	v, err := config.Try(ctx, "xyz:caCertificate")
+	if err == nil {
+		return v
+	}
+	return getEnvOrDefault("", nil, "XYZ_CA_CERTIFICATE").(string)
+}
+
+// A PEM-encoded client certificate for mutual TLS authentication.
+func GetClientCertificate(ctx *pulumi.Context) string {
+	v, err := config.Try(ctx, "xyz:clientCertificate")
+	if err == nil {
+		return v
+	}
+	return getEnvOrDefault("", nil, "XYZ_CLIENT_CERTIFICATE").(string)
+}
+
+// The PEM-encoded private key of the client certificate.
+func GetClientKey(ctx *pulumi.Context) string {
+	v, err := config.Try(ctx, "xyz:clientKey")
+	if err == nil {
+		return v
+	}
+	return getEnvOrDefault("", nil, "XYZ_CLIENT_KEY").(string)
+}
+
+// Extra HTTP headers to send with every API request. Can also be set as a JSON object in the `XYZ_HEADERS` environment variable.
+func GetHeaders(ctx *pulumi.Context) string {
+	return config.Get(ctx, "xyz:headers")
+}
+
+// The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. When set, POST requests are retried as well, since the API can detect repeated attempts.
+func GetIdempotencyKeyHeader(ctx *pulumi.Context) string {
+	v, err := config.Try(ctx, "xyz:idempotencyKeyHeader")
+	if err == nil {
+		return v
+	}
+	return getEnvOrDefault("", nil, "XYZ_IDEMPOTENCY_KEY_HEADER").(string)
+}
+
+// Disable verification of the API server's TLS certificate.
+func GetInsecureSkipVerify(ctx *pulumi.Context) bool {
+	v, err := config.TryBool(ctx, "xyz:insecureSkipVerify")
+	if err == nil {
+		return v
+	}
+	return getEnvOrDefault(false, parseEnvBool, "XYZ_INSECURE_SKIP_VERIFY").(bool)
+}
+
+// The maximum number of times a request that failed with a transient error is retried. Defaults to 3.
+func GetMaxRetries(ctx *pulumi.Context) int {
+	v, err := config.TryInt(ctx, "xyz:maxRetries")
+	if err == nil {
+		return v
+	}
+	return getEnvOrDefault(3, parseEnvInt, "XYZ_MAX_RETRIES").(int)
+}
+
+// A bearer token to send in the Authorization header of every API request.
+func GetToken(ctx *pulumi.Context) string {
+	v, err := config.Try(ctx, "xyz:token")
+	if err == nil {
+		return v
+	}
+	return getEnvOrDefault("", nil, "XYZ_TOKEN").(string)
+}
@@ -0,0 +1,30 @@
+// *** WARNING: this file was generated by the Pulumi SDK Generator. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+package xyz
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Details of one Todo
+func LookupTodo(ctx *pulumi.Context, args *LookupTodoArgs, opts ...pulumi.InvokeOption) (*LookupTodoResult, error) {
+	var rv LookupTodoResult
+	err := ctx.Invoke("xyz:index:getTodo", args, &rv, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+type LookupTodoArgs struct {
+	TodoId string `pulumi:"todoId"`
+}
+
+type LookupTodoResult struct {
+	Completed bool   `pulumi:"completed"`
+	Id        string `pulumi:"id"`
+	Order     int    `pulumi:"order"`
+	Title     string `pulumi:"title"`
+	Url       string `pulumi:"url"`
+}
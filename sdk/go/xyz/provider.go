@@ -21,6 +21,30 @@ func NewProvider(ctx *pulumi.Context,
 		args = &ProviderArgs{}
 	}
 
+	if args.BaseUrl == nil {
+		args.BaseUrl = pulumi.StringPtr(getEnvOrDefault("", nil, "XYZ_BASE_URL").(string))
+	}
+	if args.CaCertificate == nil {
+		args.CaCertificate = pulumi.StringPtr(getEnvOrDefault("", nil, "XYZ_CA_CERTIFICATE").(string))
+	}
+	if args.ClientCertificate == nil {
+		args.ClientCertificate = pulumi.StringPtr(getEnvOrDefault("", nil, "XYZ_CLIENT_CERTIFICATE").(string))
+	}
+	if args.ClientKey == nil {
+		args.ClientKey = pulumi.StringPtr(getEnvOrDefault("", nil, "XYZ_CLIENT_KEY").(string))
+	}
+	if args.IdempotencyKeyHeader == nil {
+		args.IdempotencyKeyHeader = pulumi.StringPtr(getEnvOrDefault("", nil, "XYZ_IDEMPOTENCY_KEY_HEADER").(string))
+	}
+	if args.InsecureSkipVerify == nil {
+		args.InsecureSkipVerify = pulumi.BoolPtr(getEnvOrDefault(false, parseEnvBool, "XYZ_INSECURE_SKIP_VERIFY").(bool))
+	}
+	if args.MaxRetries == nil {
+		args.MaxRetries = pulumi.IntPtr(getEnvOrDefault(3, parseEnvInt, "XYZ_MAX_RETRIES").(int))
+	}
+	if args.Token == nil {
+		args.Token = pulumi.StringPtr(getEnvOrDefault("", nil, "XYZ_TOKEN").(string))
+	}
 	var resource Provider
 	err := ctx.RegisterResource("pulumi:providers:xyz", name, args, &resource, opts...)
 	if err != nil {
@@ -30,10 +54,46 @@ func NewProvider(ctx *pulumi.Context,
 }
 
 type providerArgs struct {
+	// The base URL of the API. Defaults to `https://functodobackend.azurewebsites.net/api`.
+	BaseUrl *string `pulumi:"baseUrl"`
+	// A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.
+	CaCertificate *string `pulumi:"caCertificate"`
+	// A PEM-encoded client certificate for mutual TLS authentication.
+	ClientCertificate *string `pulumi:"clientCertificate"`
+	// The PEM-encoded private key of the client certificate.
+	ClientKey *string `pulumi:"clientKey"`
+	// Extra HTTP headers to send with every API request. Can also be set as a JSON object in the `XYZ_HEADERS` environment variable.
+	Headers map[string]string `pulumi:"headers"`
+	// The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. When set, POST requests are retried as well, since the API can detect repeated attempts.
+	IdempotencyKeyHeader *string `pulumi:"idempotencyKeyHeader"`
+	// Disable verification of the API server's TLS certificate.
+	InsecureSkipVerify *bool `pulumi:"insecureSkipVerify"`
+	// The maximum number of times a request that failed with a transient error is retried. Defaults to 3.
+	MaxRetries *int `pulumi:"maxRetries"`
+	// A bearer token to send in the Authorization header of every API request.
+	Token *string `pulumi:"token"`
 }
 
 // The set of arguments for constructing a Provider resource.
 type ProviderArgs struct {
+	// The base URL of the API. Defaults to `https://functodobackend.azurewebsites.net/api`.
+	BaseUrl pulumi.StringPtrInput
+	// A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.
+	CaCertificate pulumi.StringPtrInput
+	// A PEM-encoded client certificate for mutual TLS authentication.
+	ClientCertificate pulumi.StringPtrInput
+	// The PEM-encoded private key of the client certificate.
+	ClientKey pulumi.StringPtrInput
+	// Extra HTTP headers to send with every API request. Can also be set as a JSON object in the `XYZ_HEADERS` environment variable.
+	Headers pulumi.StringMapInput
+	// The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. When set, POST requests are retried as well, since the API can detect repeated attempts.
+	IdempotencyKeyHeader pulumi.StringPtrInput
+	// Disable verification of the API server's TLS certificate.
+	InsecureSkipVerify pulumi.BoolPtrInput
+	// The maximum number of times a request that failed with a transient error is retried. Defaults to 3.
+	MaxRetries pulumi.IntPtrInput
+	// A bearer token to send in the Authorization header of every API request.
+	Token pulumi.StringPtrInput
 }
 
 func (ProviderArgs) ElementType() reflect.Type {
@@ -0,0 +1,44 @@
+// *** WARNING: this file was generated by the Pulumi SDK Generator. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import * as pulumi from "@pulumi/pulumi";
+import * as utilities from "./utilities";
+
+let __config = new pulumi.Config("xyz");
+
+/**
+ * The base URL of the API. Defaults to `https://functodobackend.azurewebsites.net/api`.
+ */
+export let baseUrl: string | undefined = __config.get("baseUrl") || utilities.getEnv("XYZ_BASE_URL");
+/**
+ * A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.
+ */
+export let caCertificate: string | undefined = __config.get("caCertificate") || utilities.getEnv("XYZ_CA_CERTIFICATE");
+/**
+ * A PEM-encoded client certificate for mutual TLS authentication.
+ */
+export let clientCertificate: string | undefined = __config.get("clientCertificate") || utilities.getEnv("XYZ_CLIENT_CERTIFICATE");
+/**
+ * The PEM-encoded private key of the client certificate.
+ */
+export let clientKey: string | undefined = __config.get("clientKey") || utilities.getEnv("XYZ_CLIENT_KEY");
+/**
+ * Extra HTTP headers to send with every API request. Can also be set as a JSON object in the `XYZ_HEADERS` environment variable.
+ */
+export let headers: {[key: string]: string} | undefined = __config.getObject<{[key: string]: string}>("headers");
+/**
+ * The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. When set, POST requests are retried as well, since the API can detect repeated attempts.
+ */
+export let idempotencyKeyHeader: string | undefined = __config.get("idempotencyKeyHeader") || utilities.getEnv("XYZ_IDEMPOTENCY_KEY_HEADER");
+/**
+ * Disable verification of the API server's TLS certificate.
+ */
+export let insecureSkipVerify: boolean | undefined = __config.getObject<boolean>("insecureSkipVerify") || <any>utilities.getEnvBoolean("XYZ_INSECURE_SKIP_VERIFY");
+/**
+ * The maximum number of times a request that failed with a transient error is retried. Defaults to 3.
+ */
+export let maxRetries: number | undefined = __config.getObject<number>("maxRetries") || (<any>utilities.getEnvNumber("XYZ_MAX_RETRIES") || 3);
+/**
+ * A bearer token to send in the Authorization header of every API request.
+ */
+export let token: string | undefined = __config.get("token") || utilities.getEnv("XYZ_TOKEN");
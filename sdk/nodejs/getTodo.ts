@@ -0,0 +1,34 @@
+// *** WARNING: this file was generated by the Pulumi SDK Generator. ***
+// *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import * as pulumi from "@pulumi/pulumi";
+import { input as inputs, output as outputs } from "./types";
+import * as utilities from "./utilities";
+
+/**
+ * Details of one Todo
+ */
+export function getTodo(args: GetTodoArgs, opts?: pulumi.InvokeOptions): Promise<GetTodoResult> {
+    if (!opts) {
+        opts = {}
+    }
+
+    if (!opts.version) {
+        opts.version = utilities.getVersion();
+    }
+    return pulumi.runtime.invoke("xyz:index:getTodo", {
+        "todoId": args.todoId,
+    }, opts);
+}
+
+export interface GetTodoArgs {
+    readonly todoId: string;
+}
+
+export interface GetTodoResult {
+    readonly completed: boolean;
+    readonly id: string;
+    readonly order: number;
+    readonly title: string;
+    readonly url: string;
+}
@@ -5,9 +5,17 @@ import * as pulumi from "@pulumi/pulumi";
 import * as utilities from "./utilities";
 
 // Export members:
+export * from "./getTodo";
 export * from "./provider";
 export * from "./todo";
 
+// Export sub-modules:
+import * as config from "./config";
+
+export {
+    config,
+};
+
 // Import resources to register:
 import { Todo } from "./todo";
 
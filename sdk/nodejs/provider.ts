@@ -31,6 +31,15 @@ export class Provider extends pulumi.ProviderResource {
         let inputs: pulumi.Inputs = {};
         opts = opts || {};
         {
+            inputs["baseUrl"] = (args ? args.baseUrl : undefined) ?? utilities.getEnv("XYZ_BASE_URL");
+            inputs["caCertificate"] = (args ? args.caCertificate : undefined) ?? utilities.getEnv("XYZ_CA_CERTIFICATE");
+            inputs["clientCertificate"] = (args ? args.clientCertificate : undefined) ?? utilities.getEnv("XYZ_CLIENT_CERTIFICATE");
+            inputs["clientKey"] = (args ? args.clientKey : undefined) ?? utilities.getEnv("XYZ_CLIENT_KEY");
+            inputs["headers"] = pulumi.output(args ? args.headers : undefined).apply(JSON.stringify);
+            inputs["idempotencyKeyHeader"] = (args ? args.idempotencyKeyHeader : undefined) ?? utilities.getEnv("XYZ_IDEMPOTENCY_KEY_HEADER");
+            inputs["insecureSkipVerify"] = pulumi.output((args ? args.insecureSkipVerify : undefined) ?? <any>utilities.getEnvBoolean("XYZ_INSECURE_SKIP_VERIFY")).apply(JSON.stringify);
+            inputs["maxRetries"] = pulumi.output((args ? args.maxRetries : undefined) ?? (<any>utilities.getEnvNumber("XYZ_MAX_RETRIES") || 3)).apply(JSON.stringify);
+            inputs["token"] = (args ? args.token : undefined) ?? utilities.getEnv("XYZ_TOKEN");
         }
         if (!opts.version) {
             opts = pulumi.mergeOptions(opts, { version: utilities.getVersion()});
@@ -43,4 +52,40 @@ export class Provider extends pulumi.ProviderResource {
  * The set of arguments for constructing a Provider resource.
  */
 export interface ProviderArgs {
+    /**
+     * The base URL of the API. Defaults to `https://functodobackend.azurewebsites.net/api`.
+     */
+    readonly baseUrl?: pulumi.Input<string>;
+    /**
+     * A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.
+     */
+    readonly caCertificate?: pulumi.Input<string>;
+    /**
+     * A PEM-encoded client certificate for mutual TLS authentication.
+     */
+    readonly clientCertificate?: pulumi.Input<string>;
+    /**
+     * The PEM-encoded private key of the client certificate.
+     */
+    readonly clientKey?: pulumi.Input<string>;
+    /**
+     * Extra HTTP headers to send with every API request. Can also be set as a JSON object in the `XYZ_HEADERS` environment variable.
+     */
+    readonly headers?: pulumi.Input<{[key: string]: pulumi.Input<string>}>;
+    /**
+     * The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. When set, POST requests are retried as well, since the API can detect repeated attempts.
+     */
+    readonly idempotencyKeyHeader?: pulumi.Input<string>;
+    /**
+     * Disable verification of the API server's TLS certificate.
+     */
+    readonly insecureSkipVerify?: pulumi.Input<boolean>;
+    /**
+     * The maximum number of times a request that failed with a transient error is retried. Defaults to 3.
+     */
+    readonly maxRetries?: pulumi.Input<number>;
+    /**
+     * A bearer token to send in the Authorization header of every API request.
+     */
+    readonly token?: pulumi.Input<string>;
 }
@@ -3,9 +3,15 @@
 # *** Do not edit by hand unless you're certain you know what you are doing! ***
 
 # Export this package's modules as members:
+from .get_todo import *
 from .provider import *
 from .todo import *
 
+# Make subpackages available:
+from . import (
+    config,
+)
+
 def _register_module():
     import pulumi
     from . import _utilities
@@ -0,0 +1,69 @@
+# coding=utf-8
+# *** WARNING: this file was generated by the Pulumi SDK Generator. ***
+# *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import warnings
+import pulumi
+import pulumi.runtime
+from typing import Any, Mapping, Optional, Sequence, Union, overload
+from . import _utilities
+
+__all__ = [
+    'base_url',
+    'ca_certificate',
+    'client_certificate',
+    'client_key',
+    'headers',
+    'idempotency_key_header',
+    'insecure_skip_verify',
+    'max_retries',
+    'token',
+]
+
+__config__ = pulumi.Config('xyz')
+
+base_url = __config__.get('baseUrl') or _utilities.get_env('XYZ_BASE_URL')
+"""
+The base URL of the API. Defaults to `https://functodobackend.azurewebsites.net/api`.
+"""
+
+ca_certificate = __config__.get('caCertificate') or _utilities.get_env('XYZ_CA_CERTIFICATE')
+"""
+A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.
+"""
+
+client_certificate = __config__.get('clientCertificate') or _utilities.get_env('XYZ_CLIENT_CERTIFICATE')
+"""
+A PEM-encoded client certificate for mutual TLS authentication.
+"""
+
+client_key = __config__.get('clientKey') or _utilities.get_env('XYZ_CLIENT_KEY')
+"""
+The PEM-encoded private key of the client certificate.
+"""
+
+headers = __config__.get('headers')
+"""
+Extra HTTP headers to send with every API request. Can also be set as a JSON object in the `XYZ_HEADERS` environment variable.
+"""
+
+idempotency_key_header = __config__.get('idempotencyKeyHeader') or _utilities.get_env('XYZ_IDEMPOTENCY_KEY_HEADER')
+"""
+The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. When set, POST requests are retried as well, since the API can detect repeated attempts.
+"""
+
+insecure_skip_verify = __config__.get('insecureSkipVerify') or _utilities.get_env_bool('XYZ_INSECURE_SKIP_VERIFY')
+"""
+Disable verification of the API server's TLS certificate.
+"""
+
+max_retries = __config__.get('maxRetries') or (_utilities.get_env_int('XYZ_MAX_RETRIES') or 3)
+"""
+The maximum number of times a request that failed with a transient error is retried. Defaults to 3.
+"""
+
+token = __config__.get('token') or _utilities.get_env('XYZ_TOKEN')
+"""
+A bearer token to send in the Authorization header of every API request.
+"""
+
@@ -0,0 +1,93 @@
+# coding=utf-8
+# *** WARNING: this file was generated by the Pulumi SDK Generator. ***
+# *** Do not edit by hand unless you're certain you know what you are doing! ***
+
+import warnings
+import pulumi
+import pulumi.runtime
+from typing import Any, Mapping, Optional, Sequence, Union, overload
+from . import _utilities
+
+__all__ = [
+    'GetTodoResult',
+    'AwaitableGetTodoResult',
+    'get_todo',
+]
+
+@pulumi.output_type
+class GetTodoResult:
+    def __init__(__self__, completed=None, id=None, order=None, title=None, url=None):
+        if completed and not isinstance(completed, bool):
+            raise TypeError("Expected argument 'completed' to be a bool")
+        pulumi.set(__self__, "completed", completed)
+        if id and not isinstance(id, str):
+            raise TypeError("Expected argument 'id' to be a str")
+        pulumi.set(__self__, "id", id)
+        if order and not isinstance(order, int):
+            raise TypeError("Expected argument 'order' to be a int")
+        pulumi.set(__self__, "order", order)
+        if title and not isinstance(title, str):
+            raise TypeError("Expected argument 'title' to be a str")
+        pulumi.set(__self__, "title", title)
+        if url and not isinstance(url, str):
+            raise TypeError("Expected argument 'url' to be a str")
+        pulumi.set(__self__, "url", url)
+
+    @property
+    @pulumi.getter
+    def completed(self) -> bool:
+        return pulumi.get(self, "completed")
+
+    @property
+    @pulumi.getter
+    def id(self) -> str:
+        return pulumi.get(self, "id")
+
+    @property
+    @pulumi.getter
+    def order(self) -> int:
+        return pulumi.get(self, "order")
+
+    @property
+    @pulumi.getter
+    def title(self) -> str:
+        return pulumi.get(self, "title")
+
+    @property
+    @pulumi.getter
+    def url(self) -> str:
+        return pulumi.get(self, "url")
+
+
+class AwaitableGetTodoResult(GetTodoResult):
+    # pylint: disable=using-constant-test
+    def __await__(self):
+        if False:
+            yield self
+        return GetTodoResult(
+            completed=self.completed,
+            id=self.id,
+            order=self.order,
+            title=self.title,
+            url=self.url)
+
+
+def get_todo(todo_id: Optional[str] = None,
+             opts: Optional[pulumi.InvokeOptions] = None) -> AwaitableGetTodoResult:
+    """
+    Details of one Todo
+    """
+    __args__ = dict()
+    __args__['todoId'] = todo_id
+    if opts is None:
+        opts = pulumi.InvokeOptions()
+    if opts.version is None:
+        opts.version = _utilities.get_version()
+    __ret__ = pulumi.runtime.invoke('xyz:index:getTodo', __args__, opts=opts, typ=GetTodoResult).value
+
+    return AwaitableGetTodoResult(
+        completed=__ret__.completed,
+        id=__ret__.id,
+        order=__ret__.order,
+        title=__ret__.title,
+        url=__ret__.url)
@@ -12,11 +12,170 @@ __all__ = ['ProviderArgs', 'Provider']
 
 @pulumi.input_type
 class ProviderArgs:
-    def __init__(__self__):
+    def __init__(__self__, *,
+                 base_url: Optional[pulumi.Input[str]] = None,
+                 ca_certificate: Optional[pulumi.Input[str]] = None,
+                 client_certificate: Optional[pulumi.Input[str]] = None,
+                 client_key: Optional[pulumi.Input[str]] = None,
+                 headers: Optional[pulumi.Input[Mapping[str, pulumi.Input[str]]]] = None,
+                 idempotency_key_header: Optional[pulumi.Input[str]] = None,
+                 insecure_skip_verify: Optional[pulumi.Input[bool]] = None,
+                 max_retries: Optional[pulumi.Input[int]] = None,
+                 token: Optional[pulumi.Input[str]] = None):
         """
         The set of arguments for constructing a Provider resource.
+        :param pulumi.Input[str] base_url: The base URL of the API. Defaults to `https://functodobackend.azurewebsites.net/api`.
+        :param pulumi.Input[str] ca_certificate: A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.
+        :param pulumi.Input[str] client_certificate: A PEM-encoded client certificate for mutual TLS authentication.
+        :param pulumi.Input[str] client_key: The PEM-encoded private key of the client certificate.
+        :param pulumi.Input[Mapping[str, pulumi.Input[str]]] headers: Extra HTTP headers to send with every API request. Can also be set as a JSON object in the `XYZ_HEADERS` environment variable.
+        :param pulumi.Input[str] idempotency_key_header: The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. When set, POST requests are retried as well, since the API can detect repeated attempts.
+        :param pulumi.Input[bool] insecure_skip_verify: Disable verification of the API server's TLS certificate.
+        :param pulumi.Input[int] max_retries: The maximum number of times a request that failed with a transient error is retried. Defaults to 3.
+        :param pulumi.Input[str] token: A bearer token to send in the Authorization header of every API request.
         """
-        pass
+        if base_url is None:
+            base_url = _utilities.get_env('XYZ_BASE_URL')
+        if base_url is not None:
+            pulumi.set(__self__, "base_url", base_url)
+        if ca_certificate is None:
+            ca_certificate = _utilities.get_env('XYZ_CA_CERTIFICATE')
+        if ca_certificate is not None:
+            pulumi.set(__self__, "ca_certificate", ca_certificate)
+        if client_certificate is None:
+            client_certificate = _utilities.get_env('XYZ_CLIENT_CERTIFICATE')
+        if client_certificate is not None:
+            pulumi.set(__self__, "client_certificate", client_certificate)
+        if client_key is None:
+            client_key = _utilities.get_env('XYZ_CLIENT_KEY')
+        if client_key is not None:
+            pulumi.set(__self__, "client_key", client_key)
+        if headers is not None:
+            pulumi.set(__self__, "headers", headers)
+        if idempotency_key_header is None:
+            idempotency_key_header = _utilities.get_env('XYZ_IDEMPOTENCY_KEY_HEADER')
+        if idempotency_key_header is not None:
+            pulumi.set(__self__, "idempotency_key_header", idempotency_key_header)
+        if insecure_skip_verify is None:
+            insecure_skip_verify = _utilities.get_env_bool('XYZ_INSECURE_SKIP_VERIFY')
+        if insecure_skip_verify is not None:
+            pulumi.set(__self__, "insecure_skip_verify", insecure_skip_verify)
+        if max_retries is None:
+            max_retries = (_utilities.get_env_int('XYZ_MAX_RETRIES') or 3)
+        if max_retries is not None:
+            pulumi.set(__self__, "max_retries", max_retries)
+        if token is None:
+            token = _utilities.get_env('XYZ_TOKEN')
+        if token is not None:
+            pulumi.set(__self__, "token", token)
+
+    @property
+    @pulumi.getter(name="baseUrl")
+    def base_url(self) -> Optional[pulumi.Input[str]]:
+        """
+        The base URL of the API. Defaults to `https://functodobackend.azurewebsites.net/api`.
+        """
+        return pulumi.get(self, "base_url")
+
+    @base_url.setter
+    def base_url(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "base_url", value)
+
+    @property
+    @pulumi.getter(name="caCertificate")
+    def ca_certificate(self) -> Optional[pulumi.Input[str]]:
+        """
+        A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.
+        """
+        return pulumi.get(self, "ca_certificate")
+
+    @ca_certificate.setter
+    def ca_certificate(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "ca_certificate", value)
+
+    @property
+    @pulumi.getter(name="clientCertificate")
+    def client_certificate(self) -> Optional[pulumi.Input[str]]:
+        """
+        A PEM-encoded client certificate for mutual TLS authentication.
+        """
+        return pulumi.get(self, "client_certificate")
+
+    @client_certificate.setter
+    def client_certificate(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "client_certificate", value)
+
+    @property
+    @pulumi.getter(name="clientKey")
+    def client_key(self) -> Optional[pulumi.Input[str]]:
+        """
+        The PEM-encoded private key of the client certificate.
+        """
+        return pulumi.get(self, "client_key")
+
+    @client_key.setter
+    def client_key(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "client_key", value)
+
+    @property
+    @pulumi.getter
+    def headers(self) -> Optional[pulumi.Input[Mapping[str, pulumi.Input[str]]]]:
+        """
+        Extra HTTP headers to send with every API request. Can also be set as a JSON object in the `XYZ_HEADERS` environment variable.
+        """
+        return pulumi.get(self, "headers")
+
+    @headers.setter
+    def headers(self, value: Optional[pulumi.Input[Mapping[str, pulumi.Input[str]]]]):
+        pulumi.set(self, "headers", value)
+
+    @property
+    @pulumi.getter(name="idempotencyKeyHeader")
+    def idempotency_key_header(self) -> Optional[pulumi.Input[str]]:
+        """
+        The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. When set, POST requests are retried as well, since the API can detect repeated attempts.
+        """
+        return pulumi.get(self, "idempotency_key_header")
+
+    @idempotency_key_header.setter
+    def idempotency_key_header(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "idempotency_key_header", value)
+
+    @property
+    @pulumi.getter(name="insecureSkipVerify")
+    def insecure_skip_verify(self) -> Optional[pulumi.Input[bool]]:
+        """
+        Disable verification of the API server's TLS certificate.
+        """
+        return pulumi.get(self, "insecure_skip_verify")
+
+    @insecure_skip_verify.setter
+    def insecure_skip_verify(self, value: Optional[pulumi.Input[bool]]):
+        pulumi.set(self, "insecure_skip_verify", value)
+
+    @property
+    @pulumi.getter(name="maxRetries")
+    def max_retries(self) -> Optional[pulumi.Input[int]]:
+        """
+        The maximum number of times a request that failed with a transient error is retried. Defaults to 3.
+        """
+        return pulumi.get(self, "max_retries")
+
+    @max_retries.setter
+    def max_retries(self, value: Optional[pulumi.Input[int]]):
+        pulumi.set(self, "max_retries", value)
+
+    @property
+    @pulumi.getter
+    def token(self) -> Optional[pulumi.Input[str]]:
+        """
+        A bearer token to send in the Authorization header of every API request.
+        """
+        return pulumi.get(self, "token")
+
+    @token.setter
+    def token(self, value: Optional[pulumi.Input[str]]):
+        pulumi.set(self, "token", value)
 
 
 class Provider(pulumi.ProviderResource):
@@ -24,11 +183,29 @@ class Provider(pulumi.ProviderResource):
     def __init__(__self__,
                  resource_name: str,
                  opts: Optional[pulumi.ResourceOptions] = None,
+                 base_url: Optional[pulumi.Input[str]] = None,
+                 ca_certificate: Optional[pulumi.Input[str]] = None,
+                 client_certificate: Optional[pulumi.Input[str]] = None,
+                 client_key: Optional[pulumi.Input[str]] = None,
+                 headers: Optional[pulumi.Input[Mapping[str, pulumi.Input[str]]]] = None,
+                 idempotency_key_header: Optional[pulumi.Input[str]] = None,
+                 insecure_skip_verify: Optional[pulumi.Input[bool]] = None,
+                 max_retries: Optional[pulumi.Input[int]] = None,
+                 token: Optional[pulumi.Input[str]] = None,
                  __props__=None):
         """
         Create a Xyz resource with the given unique name, props, and options.
         :param str resource_name: The name of the resource.
         :param pulumi.ResourceOptions opts: Options for the resource.
+        :param pulumi.Input[str] base_url: The base URL of the API. Defaults to `https://functodobackend.azurewebsites.net/api`.
+        :param pulumi.Input[str] ca_certificate: A PEM-encoded CA certificate bundle used to verify the API server's TLS certificate.
+        :param pulumi.Input[str] client_certificate: A PEM-encoded client certificate for mutual TLS authentication.
+        :param pulumi.Input[str] client_key: The PEM-encoded private key of the client certificate.
+        :param pulumi.Input[Mapping[str, pulumi.Input[str]]] headers: Extra HTTP headers to send with every API request. Can also be set as a JSON object in the `XYZ_HEADERS` environment variable.
+        :param pulumi.Input[str] idempotency_key_header: The name of a header that carries a unique key per request, e.g. `Idempotency-Key`. When set, POST requests are retried as well, since the API can detect repeated attempts.
+        :param pulumi.Input[bool] insecure_skip_verify: Disable verification of the API server's TLS certificate.
+        :param pulumi.Input[int] max_retries: The maximum number of times a request that failed with a transient error is retried. Defaults to 3.
+        :param pulumi.Input[str] token: A bearer token to send in the Authorization header of every API request.
         """
         ...
     @overload
@@ -53,6 +230,15 @@ class Provider(pulumi.ProviderResource):
     def _internal_init(__self__,
                  resource_name: str,
                  opts: Optional[pulumi.ResourceOptions] = None,
+                 base_url: Optional[pulumi.Input[str]] = None,
+                 ca_certificate: Optional[pulumi.Input[str]] = None,
+                 client_certificate: Optional[pulumi.Input[str]] = None,
+                 client_key: Optional[pulumi.Input[str]] = None,
+                 headers: Optional[pulumi.Input[Mapping[str, pulumi.Input[str]]]] = None,
+                 idempotency_key_header: Optional[pulumi.Input[str]] = None,
+                 insecure_skip_verify: Optional[pulumi.Input[bool]] = None,
+                 max_retries: Optional[pulumi.Input[int]] = None,
+                 token: Optional[pulumi.Input[str]] = None,
                  __props__=None):
         if opts is None:
             opts = pulumi.ResourceOptions()
@@ -65,6 +251,31 @@ class Provider(pulumi.ProviderResource):
                 raise TypeError('__props__ is only valid when passed in combination with a valid opts.id to get an existing resource')
             __props__ = ProviderArgs.__new__(ProviderArgs)
 
+            if base_url is None:
+                base_url = _utilities.get_env('XYZ_BASE_URL')
+            __props__.__dict__["base_url"] = base_url
+            if ca_certificate is None:
+                ca_certificate = _utilities.get_env('XYZ_CA_CERTIFICATE')
+            __props__.__dict__["ca_certificate"] = ca_certificate
+            if client_certificate is None:
+                client_certificate = _utilities.get_env('XYZ_CLIENT_CERTIFICATE')
+            __props__.__dict__["client_certificate"] = client_certificate
+            if client_key is None:
+                client_key = _utilities.get_env('XYZ_CLIENT_KEY')
+            __props__.__dict__["client_key"] = client_key
+            __props__.__dict__["headers"] = pulumi.Output.from_input(headers).apply(pulumi.runtime.to_json) if headers is not None else None
+            if idempotency_key_header is None:
+                idempotency_key_header = _utilities.get_env('XYZ_IDEMPOTENCY_KEY_HEADER')
+            __props__.__dict__["idempotency_key_header"] = idempotency_key_header
+            if insecure_skip_verify is None:
+                insecure_skip_verify = _utilities.get_env_bool('XYZ_INSECURE_SKIP_VERIFY')
+            __props__.__dict__["insecure_skip_verify"] = pulumi.Output.from_input(insecure_skip_verify).apply(pulumi.runtime.to_json) if insecure_skip_verify is not None else None
+            if max_retries is None:
+                max_retries = (_utilities.get_env_int('XYZ_MAX_RETRIES') or 3)
+            __props__.__dict__["max_retries"] = pulumi.Output.from_input(max_retries).apply(pulumi.runtime.to_json) if max_retries is not None else None
+            if token is None:
+                token = _utilities.get_env('XYZ_TOKEN')
+            __props__.__dict__["token"] = token
         super(Provider, __self__).__init__(
             'xyz',
             resource_name,